@@ -34,7 +34,7 @@ func (e *prettyConsoleEncoder) AppendBool(b bool) {
 
 func (e *prettyConsoleEncoder) AppendByteString(bytes []byte) {
 	e.addSeparator()
-	e.appendSafeByte(bytes)
+	addStringValue(e, bytes, e._listSepComma)
 
 	e.inList = true
 	e.setListSep(e._listSepComma)
@@ -77,7 +77,7 @@ func (e *prettyConsoleEncoder) AppendInt64(i int64) {
 
 func (e *prettyConsoleEncoder) AppendString(s string) {
 	e.addSeparator()
-	e.addSafeString(s)
+	addStringValue(e, s, e._listSepComma)
 
 	e.inList = true
 	e.setListSep(e._listSepComma)
@@ -93,11 +93,12 @@ func (e *prettyConsoleEncoder) AppendUint64(u uint64) {
 
 func (e *prettyConsoleEncoder) AppendDuration(duration time.Duration) {
 	e.addSeparator()
+	duration = e.opts.values.duration(duration)
 	cur := e.buf.Len()
 	// The callback appends too, and must not repeat the separator
 	e.inList = false
 	if e.cfg.EncodeDuration != nil {
-		e.cfg.EncodeDuration(duration, e)
+		e.safeCall(func() { e.cfg.EncodeDuration(duration, e) })
 	}
 	if cur == e.buf.Len() {
 		// User-supplied EncodeDuration is absent or a no-op. Fall back to
@@ -111,18 +112,8 @@ func (e *prettyConsoleEncoder) AppendDuration(duration time.Duration) {
 
 func (e *prettyConsoleEncoder) AppendTime(t time.Time) {
 	e.addSeparator()
-	cur := e.buf.Len()
-	// The callback appends too, and must not repeat the separator. It also
-	// emits its own ANSI colour codes (e.g. DefaultTimeEncoder), which the
-	// escaping appender would mangle, so hand it the raw one.
-	e.inList = false
-	if e.cfg.EncodeTime != nil {
-		e.cfg.EncodeTime(t, rawStringAppender{e})
-	}
-	if cur == e.buf.Len() {
-		// User-supplied EncodeTime is absent or a no-op. Fall back to RFC3339
-		e.buf.AppendTime(t, time.RFC3339)
-	}
+	// As in AddTime, the configured time encoder is for the log's time.
+	e.appendTimeValue(t)
 
 	e.inList = true
 	e.setListSep(e._listSepComma)
@@ -130,23 +121,33 @@ func (e *prettyConsoleEncoder) AppendTime(t time.Time) {
 
 func (e *prettyConsoleEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
 	e.addSeparator()
-	enc := e.clone()
+	enc := e.nest()
 	enc.OpenNamespace("")
-	enc.colorizeAtLevel("[")
-	enc.inList = false
-	l := enc.buf.Len()
-
-	if err := marshaler.MarshalLogArray(enc); err != nil {
-		return err
-	}
-	if bytes.ContainsRune(enc.buf.Bytes()[l:], '\n') {
-		enc.buf.AppendString(e.cfg.LineEnding)
-		appendSpaces(enc.buf, enc.namespaceIndent-1)
+	start := enc.buf.Len()
+	if enc.tooDeep() {
+		enc.addMarker("", "<max depth>")
+	} else {
+		enc.colorizeAtLevel("[")
+		enc.inList = false
+		l := enc.buf.Len()
+
+		p, err := marshalArray(marshaler, enc)
+		if p != "" {
+			enc.replaceWithPanic(start, "", p)
+		} else {
+			if bytes.ContainsRune(enc.buf.Bytes()[l:], '\n') {
+				enc.buf.AppendString(e.cfg.LineEnding)
+				appendSpaces(enc.buf, enc.namespaceIndent-1)
+			}
+			enc.colorizeAtLevel("]")
+			if err != nil {
+				enc.addMarshalError("", err)
+			}
+		}
 	}
-	enc.colorizeAtLevel("]")
+	enc.truncate(start, "")
 
-	_, _ = e.buf.Write(enc.buf.Bytes())
-	putPrettyConsoleEncoder(enc)
+	e.unnest(enc)
 
 	e.inList = true
 	e.setListSep(e._listSepComma)
@@ -155,24 +156,34 @@ func (e *prettyConsoleEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) err
 
 func (e *prettyConsoleEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
 	e.addSeparator()
-	enc := e.clone()
+	enc := e.nest()
 	enc.OpenNamespace("")
-	enc.colorizeAtLevel("{")
-	enc.inList = false
-	enc.keyPrefix = ""
-	l := enc.buf.Len()
-
-	if err := marshaler.MarshalLogObject(enc); err != nil {
-		return err
-	}
-	if bytes.ContainsRune(enc.buf.Bytes()[l:], '\n') {
-		enc.buf.AppendString(e.cfg.LineEnding)
-		appendSpaces(enc.buf, enc.namespaceIndent-1)
+	start := enc.buf.Len()
+	if enc.tooDeep() {
+		enc.addMarker("", "<max depth>")
+	} else {
+		enc.colorizeAtLevel("{")
+		enc.inList = false
+		enc.keyPrefix = ""
+		l := enc.buf.Len()
+
+		p, err := marshalObject(marshaler, enc)
+		if p != "" {
+			enc.replaceWithPanic(start, "", p)
+		} else {
+			if bytes.ContainsRune(enc.buf.Bytes()[l:], '\n') {
+				enc.buf.AppendString(e.cfg.LineEnding)
+				appendSpaces(enc.buf, enc.namespaceIndent-1)
+			}
+			enc.colorizeAtLevel("}")
+			if err != nil {
+				enc.addMarshalError("", err)
+			}
+		}
 	}
-	enc.colorizeAtLevel("}")
+	enc.truncate(start, "")
 
-	_, _ = e.buf.Write(enc.buf.Bytes())
-	putPrettyConsoleEncoder(enc)
+	e.unnest(enc)
 
 	e.inList = true
 	e.setListSep(e._listSepComma)
@@ -190,9 +201,15 @@ func (e *prettyConsoleEncoder) AppendReflected(value interface{}) error {
 		buf:        enc.buf,
 		indent:     enc.namespaceIndent,
 		lineEnding: []byte(e.cfg.LineEnding),
+		maxDepth:   e.opts.maxDepth,
+		values:     &e.opts.values,
 	}
 	if e.cfg.NewReflectedEncoder != nil {
-		if err := e.cfg.NewReflectedEncoder(iw).Encode(value); err != nil {
+		p, err := encodeReflected(e.cfg.NewReflectedEncoder, iw, value)
+		if p != "" {
+			enc.replaceWithPanic(l, "", p)
+		} else if err != nil {
+			putPrettyConsoleEncoder(enc)
 			return err
 		}
 	}
@@ -266,9 +266,9 @@ func TestArraysOfEverything(t *testing.T) {
 	)
 	assert.Contains(t, out, "durations=[1s, 2m0s]")
 	assert.Contains(t, out, "uintptrs=[1, 2]")
-	// AppendTime uses the configured Kitchen encoder, whose colour codes
-	// must arrive raw (stripped here), not escaped into \u001b text.
-	assert.Contains(t, out, "times=[4:33PM, 4:34PM]")
+	// AppendTime ignores the configured Kitchen encoder, which is for the
+	// entry's own time, and writes times as AddTime does.
+	assert.Contains(t, out, "times=[2022-06-19T16:33:42Z, 2022-06-19T16:34:42Z]")
 	assert.NotContains(t, out, `\u001b`)
 	assert.Contains(t, out, "e1")
 	assert.Contains(t, out, "e2")
@@ -276,12 +276,14 @@ func TestArraysOfEverything(t *testing.T) {
 	assert.Contains(t, out, "nested_empty=[[]]")
 }
 
-// TestArrayElementErrors drives the error-returning branches of
-// AppendObject and AppendArray. testArray ignores element errors, so
-// encoding continues with the remaining elements.
+// TestArrayElementErrors drives the error branches of AppendObject and
+// AppendArray: partial elements are kept and annotated with the error.
 func TestArrayElementErrors(t *testing.T) {
-	out := encodePlain(t, zap.Array("arr", testArray{failingMarshaler{}, failingArrayMarshaler{}, "after"}))
-	assert.Contains(t, out, "after")
+	out := encodePlain(t, zap.Array("arr", testArray{partialMarshaler{}, partialArrayMarshaler{}, failingMarshaler{}, "after"}))
+	assert.Equal(t, "> msg\n  ↳ arr=[{name=Big Bird age=18} Error=boom\\nagain, \n"+
+		"         [1, 2] Error=boom, \n"+
+		"         {} Error=boom, after\n"+
+		"        ]\n", out)
 }
 
 // TestArrayWithNestedNamespaces covers multi-line objects nested inside
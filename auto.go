@@ -0,0 +1,60 @@
+package prettyconsole
+
+import (
+	"os"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewAutoConfig returns a zap.Config for services that run both on
+// developer machines and in production. It is zap.NewProductionConfig,
+// with the same level, sampling and keys, but logging through the
+// "pretty_console" encoding (or "pretty_console_monochrome", see
+// NewConfig) when a person is likely to be reading:
+//
+//   - PRETTYCONSOLE_DEV set to a true value, such as 1 or true, always
+//     selects pretty output, and a false value always selects JSON;
+//     other values are ignored;
+//   - otherwise pretty output is selected when stderr is a terminal.
+//
+// Sharing the keys means JSON logs can later be made pretty with the
+// zap-pretty command or JSONDecoder.
+func NewAutoConfig() zap.Config {
+	cfg := zap.NewProductionConfig()
+	if !autoPretty() {
+		return cfg
+	}
+
+	cfg.EncoderConfig = encoderConfigWithKeys(cfg.EncoderConfig)
+	cfg.Encoding = NewConfig().Encoding
+	return cfg
+}
+
+// encoderConfigWithKeys returns NewEncoderConfig with the keys of keys, so
+// both configs write and decode the same parts of an entry.
+func encoderConfigWithKeys(keys zapcore.EncoderConfig) zapcore.EncoderConfig {
+	ec := NewEncoderConfig()
+	ec.MessageKey = keys.MessageKey
+	ec.LevelKey = keys.LevelKey
+	ec.TimeKey = keys.TimeKey
+	ec.NameKey = keys.NameKey
+	ec.CallerKey = keys.CallerKey
+	ec.FunctionKey = keys.FunctionKey
+	ec.StacktraceKey = keys.StacktraceKey
+	return ec
+}
+
+// NewAutoLogger builds a logger from NewAutoConfig.
+func NewAutoLogger(opts ...zap.Option) (*zap.Logger, error) {
+	return NewAutoConfig().Build(opts...)
+}
+
+// autoPretty reports whether NewAutoConfig should select pretty output.
+func autoPretty() bool {
+	if dev, err := strconv.ParseBool(os.Getenv("PRETTYCONSOLE_DEV")); err == nil {
+		return dev
+	}
+	return isTerminal(os.Stderr)
+}
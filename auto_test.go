@@ -0,0 +1,81 @@
+package prettyconsole
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// autoLog logs through NewAutoLogger into a temporary file and returns the
+// output.
+func autoLog(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "log")
+	cfg := NewAutoConfig()
+	cfg.OutputPaths = []string{path}
+	logger, err := cfg.Build()
+	require.NoError(t, err)
+	logger.Debug("hidden")
+	logger.Info("m", zap.String("k", "v"))
+	require.NoError(t, logger.Sync())
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestNewAutoConfig(t *testing.T) {
+	prod := zap.NewProductionConfig()
+
+	t.Setenv("PRETTYCONSOLE_DEV", "false")
+	json := NewAutoConfig()
+	assert.Equal(t, "json", json.Encoding)
+	out := autoLog(t)
+	assert.True(t, strings.HasPrefix(out, `{"level":"info","ts":`), out)
+
+	// JSON output decodes with the same config it was written with.
+	ent, fields, err := NewJSONDecoder(json.EncoderConfig).Decode([]byte(out))
+	require.NoError(t, err)
+	assert.Equal(t, "m", ent.Message)
+	assert.Equal(t, zapcore.InfoLevel, ent.Level)
+	assert.True(t, ent.Caller.Defined)
+	assert.Equal(t, []zapcore.Field{zap.String("k", "v")}, fields)
+
+	t.Setenv("PRETTYCONSOLE_DEV", "1")
+	t.Setenv("NO_COLOR", "1")
+	pretty := NewAutoConfig()
+	assert.Equal(t, "pretty_console_monochrome", pretty.Encoding)
+	out = autoLog(t)
+	assert.Regexp(t, `^\d+:\d\d[AP]M INF \S*auto_test\.go:\d+ > m k=v\n$`, out)
+
+	// Everything but the encoding is shared.
+	for _, cfg := range []zap.Config{json, pretty} {
+		assert.Equal(t, prod.Level.Level(), cfg.Level.Level())
+		assert.Equal(t, prod.Sampling, cfg.Sampling)
+		assert.Equal(t, prod.Development, cfg.Development)
+		assert.Equal(t, prod.OutputPaths, cfg.OutputPaths)
+		ec := cfg.EncoderConfig
+		assert.Equal(t,
+			[]string{prod.EncoderConfig.MessageKey, prod.EncoderConfig.LevelKey, prod.EncoderConfig.TimeKey, prod.EncoderConfig.NameKey,
+				prod.EncoderConfig.CallerKey, prod.EncoderConfig.FunctionKey, prod.EncoderConfig.StacktraceKey},
+			[]string{ec.MessageKey, ec.LevelKey, ec.TimeKey, ec.NameKey, ec.CallerKey, ec.FunctionKey, ec.StacktraceKey})
+	}
+
+	_, err = NewAutoLogger()
+	require.NoError(t, err)
+}
+
+func TestAutoPretty(t *testing.T) {
+	for v, want := range map[string]bool{"1": true, "TRUE": true, "0": false, "false": false} {
+		t.Setenv("PRETTYCONSOLE_DEV", v)
+		assert.Equal(t, want, autoPretty(), v)
+	}
+	// Anything else falls back to checking stderr.
+	t.Setenv("PRETTYCONSOLE_DEV", "maybe")
+	assert.Equal(t, isTerminal(os.Stderr), autoPretty())
+}
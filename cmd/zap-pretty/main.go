@@ -0,0 +1,130 @@
+// Command zap-pretty renders zap JSON logs with the pretty console encoder.
+//
+// It reads JSON lines from the files named on the command line, or from
+// stdin if there are none, and writes them to stdout as the pretty console
+// encoder would have logged them. Lines that are not JSON objects are passed
+// through untouched, so it can sit at the end of any pipe:
+//
+//	kubectl logs my-pod | zap-pretty
+//
+// The keys default to those of zap.NewProductionEncoderConfig and can be
+// changed with flags; run zap-pretty -h for the full list.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	prettyconsole "github.com/thessem/zap-prettyconsole"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "zap-pretty:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	keys := zap.NewProductionEncoderConfig()
+	fs := flag.NewFlagSet("zap-pretty", flag.ContinueOnError)
+	fs.StringVar(&keys.MessageKey, "message-key", keys.MessageKey, "JSON key of the log message")
+	fs.StringVar(&keys.LevelKey, "level-key", keys.LevelKey, "JSON key of the log level")
+	fs.StringVar(&keys.TimeKey, "time-key", keys.TimeKey, "JSON key of the log time")
+	fs.StringVar(&keys.NameKey, "name-key", keys.NameKey, "JSON key of the logger name")
+	fs.StringVar(&keys.CallerKey, "caller-key", keys.CallerKey, "JSON key of the caller")
+	fs.StringVar(&keys.FunctionKey, "function-key", "function", "JSON key of the calling function")
+	fs.StringVar(&keys.StacktraceKey, "stacktrace-key", keys.StacktraceKey, "JSON key of the stacktrace")
+	timeFormat := fs.String("time-format", time.Kitchen, "Go time layout for entry times")
+	colour := fs.String("color", "auto", "colourise output: auto, always or never")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var colourOn bool
+	switch *colour {
+	case "auto":
+		colourOn = prettyconsole.ColourEnabled(stdout)
+	case "always":
+		colourOn = true
+	case "never":
+	default:
+		return fmt.Errorf("invalid -color %q: want auto, always or never", *colour)
+	}
+
+	cfg := prettyconsole.NewEncoderConfig()
+	cfg.CallerKey = "C"
+	cfg.FunctionKey = "F"
+	base := prettyconsole.DefaultTimeEncoder(*timeFormat)
+	cfg.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		// Lines without a time would otherwise all claim midnight.
+		if !t.IsZero() {
+			base(t, enc)
+		}
+	}
+	enc := prettyconsole.NewEncoder(cfg, prettyconsole.WithColour(colourOn))
+
+	dec := prettyconsole.NewJSONDecoder(keys)
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+	if fs.NArg() == 0 {
+		return prettify(dec, enc, stdin, out)
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = prettify(dec, enc, f, out)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prettify copies r to w line by line, re-rendering every line that decodes
+// as a zap JSON entry.
+func prettify(dec *prettyconsole.JSONDecoder, enc zapcore.Encoder, r io.Reader, w *bufio.Writer) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if werr := prettifyLine(dec, enc, line, w); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func prettifyLine(dec *prettyconsole.JSONDecoder, enc zapcore.Encoder, line []byte, w *bufio.Writer) error {
+	ent, fields, err := dec.Decode(line)
+	if err != nil {
+		_, err = w.Write(line)
+		return err
+	}
+	buf, err := enc.EncodeEntry(ent, fields)
+	if err != nil {
+		_, err = w.Write(line)
+		return err
+	}
+	defer buf.Free()
+	_, err = w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// jsonLog logs through zap's production JSON encoder, as the tool's input
+// would have been written.
+func jsonLog(t *testing.T, log func(*zap.Logger)) string {
+	t.Helper()
+	var buf bytes.Buffer
+	ec := zap.NewProductionEncoderConfig()
+	logger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(ec), zapcore.AddSync(&buf), zapcore.DebugLevel),
+		zap.AddCaller())
+	log(logger)
+	return buf.String()
+}
+
+func runTool(t *testing.T, stdin string, args ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	require.NoError(t, run(append([]string{"-color", "never"}, args...), strings.NewReader(stdin), &out))
+	return out.String()
+}
+
+type testObject struct{}
+
+func (testObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", "Big Bird")
+	enc.AddInt("age", 18)
+	return enc.AddArray("tags", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendString("yellow")
+		enc.AppendBool(true)
+		return nil
+	}))
+}
+
+func TestPrettify(t *testing.T) {
+	in := jsonLog(t, func(l *zap.Logger) {
+		l.Named("svc").Warn("hello\nthere",
+			zap.Float64("pi", 3.5),
+			zap.Object("user", testObject{}),
+			zap.Namespace("ns"),
+			zap.Bool("ok", true),
+		)
+	})
+	out := runTool(t, in)
+	assert.Contains(t, out, "WRN svc ")
+	assert.Contains(t, out, "main_test.go:")
+	assert.Contains(t, out, "> hello\\nthere pi=3.5")
+	assert.Contains(t, out, "↳ ns.ok=true")
+	assert.Contains(t, out, "↳ user.age=18 .name=Big Bird")
+	assert.Contains(t, out, ".tags=[yellow, true]")
+	assert.NotContains(t, out, "\x1b")
+	assert.NotContains(t, out, `"level"`)
+}
+
+func TestPrettifyPassesThroughNonJSON(t *testing.T) {
+	in := "plain text\n[1, 2]\n{not json\n" + jsonLog(t, func(l *zap.Logger) { l.Info("m") }) + "no newline"
+	out := runTool(t, in)
+	assert.True(t, strings.HasPrefix(out, "plain text\n[1, 2]\n{not json\n"), out)
+	assert.True(t, strings.HasSuffix(out, "> m\nno newline"), out)
+}
+
+func TestPrettifyVerboseErrorsAndStacktraces(t *testing.T) {
+	in := `{"level":"error","msg":"m","error":"boom","errorVerbose":"boom\nmain.main\n\tmain.go:1","stacktrace":"main.main\n\t/src/main.go:1"}` + "\n"
+	out := runTool(t, in)
+	assert.Contains(t, out, "error=boom")
+	assert.Contains(t, out, "↳ error=boom\n          .detail=boom\n                  main.main\n                  \tmain.go:1")
+	assert.NotContains(t, out, "errorVerbose")
+	assert.Contains(t, out, "↳ stacktrace=main.main /src/main.go:1\n")
+}
+
+func TestPrettifyFiles(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log")
+	require.NoError(t, os.WriteFile(a, []byte(`{"level":"info","msg":"from a"}`+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(b, []byte(`{"level":"debug","msg":"from b"}`+"\n"), 0o644))
+	out := runTool(t, "ignored stdin\n", a, b)
+	assert.Equal(t, "INF > from a\nDBG > from b\n", out)
+}
+
+func TestCustomKeysAndFlags(t *testing.T) {
+	out := runTool(t, `{"L":"WARN","M":"custom","T":"2024-01-15T14:30:45Z"}`+"\n",
+		"-level-key", "L", "-message-key", "M", "-time-key", "T", "-time-format", time.RFC3339)
+	assert.Equal(t, "2024-01-15T14:30:45Z WRN > custom\n", out)
+
+	var buf bytes.Buffer
+	assert.Error(t, run([]string{"-color", "sometimes"}, strings.NewReader(""), &buf))
+	assert.Error(t, run([]string{filepath.Join(t.TempDir(), "missing.log")}, strings.NewReader(""), &buf))
+	assert.NoError(t, run([]string{"-color", "always"}, strings.NewReader(`{"msg":"m"}`), &buf))
+	assert.Contains(t, buf.String(), "\x1b[")
+}
@@ -0,0 +1,212 @@
+package prettyconsole
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Colour is a terminal foreground colour: one of the 16 standard colours, a
+// 256-colour palette index (Colour256) or a 24-bit colour (RGB). The zero
+// value is no colour.
+type Colour uint32
+
+// The top byte of a Colour tags its kind. Standard colours keep their SGR
+// code in the low byte, palette colours their index, and RGB colours their
+// channels in the low three bytes.
+const (
+	colourBasic Colour = 1 << 24
+	colour256   Colour = 2 << 24
+	colourRGB   Colour = 3 << 24
+	colourKind  Colour = 0xff << 24
+)
+
+// The 8 standard terminal colours.
+const (
+	Black Colour = colourBasic | (30 + iota)
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+)
+
+// The 8 bright terminal colours. BrightBlack is the dark grey the default
+// theme uses for timestamps.
+const (
+	BrightBlack Colour = colourBasic | (90 + iota)
+	BrightRed
+	BrightGreen
+	BrightYellow
+	BrightBlue
+	BrightMagenta
+	BrightCyan
+	BrightWhite
+)
+
+// Colour256 returns a colour from the 256-colour palette: 0-15 are the
+// standard colours, 16-231 a 6x6x6 colour cube and 232-255 a grey ramp.
+func Colour256(index uint8) Colour {
+	return colour256 | Colour(index)
+}
+
+// RGB returns a 24-bit "truecolor" colour.
+func RGB(r, g, b uint8) Colour {
+	return colourRGB | Colour(r)<<16 | Colour(g)<<8 | Colour(b)
+}
+
+// sgr returns the escape sequence selecting this colour.
+func (c Colour) sgr() string {
+	switch c & colourKind {
+	case colourBasic:
+		return "\x1b[" + strconv.Itoa(int(c&0xff)) + "m"
+	case colour256:
+		return "\x1b[38;5;" + strconv.Itoa(int(c&0xff)) + "m"
+	case colourRGB:
+		r, g, b := c.rgb()
+		return "\x1b[38;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b)) + "m"
+	}
+	return ""
+}
+
+// ColourDepth is the number of colours a terminal can display.
+type ColourDepth int
+
+const (
+	// Colours16 is the 8 standard colours and their bright variants.
+	Colours16 ColourDepth = iota
+	// Colours256 is the xterm 256-colour palette.
+	Colours256
+	// TrueColour is 24-bit RGB colour.
+	TrueColour
+)
+
+// DetectColourDepth guesses the terminal's colour depth from the
+// environment: COLORTERM=truecolor (or 24bit) means TrueColour, a TERM
+// mentioning 256 colours means Colours256, and anything else Colours16.
+func DetectColourDepth() ColourDepth {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColour
+	}
+	term := os.Getenv("TERM")
+	switch {
+	case strings.HasSuffix(term, "-direct"), strings.Contains(term, "truecolor"):
+		return TrueColour
+	case strings.Contains(term, "256"):
+		return Colours256
+	}
+	return Colours16
+}
+
+// downsample returns the closest colour that fits in the given depth.
+func (c Colour) downsample(depth ColourDepth) Colour {
+	switch c & colourKind {
+	case colour256:
+		if depth < Colours256 {
+			return nearestBasic(paletteRGB(uint8(c)))
+		}
+	case colourRGB:
+		switch depth {
+		case Colours16:
+			return nearestBasic(c.rgb())
+		case Colours256:
+			return nearest256(c.rgb())
+		}
+	}
+	return c
+}
+
+func (c Colour) rgb() (r, g, b uint8) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c)
+}
+
+// basicRGB holds xterm's default values for the 16 standard colours, in
+// palette order.
+var basicRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 256-colour palette's 6x6x6 cube.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// paletteRGB returns the RGB value of a 256-colour palette index.
+func paletteRGB(i uint8) (r, g, b uint8) {
+	switch {
+	case i < 16:
+		return basicRGB[i][0], basicRGB[i][1], basicRGB[i][2]
+	case i < 232:
+		i -= 16
+		return cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]
+	default:
+		v := 8 + 10*(i-232)
+		return v, v, v
+	}
+}
+
+// basicColour converts a standard palette index (0-15) to its SGR colour.
+func basicColour(i int) Colour {
+	if i < 8 {
+		return colourBasic | Colour(30+i)
+	}
+	return colourBasic | Colour(90+i-8)
+}
+
+func nearestBasic(r, g, b uint8) Colour {
+	best, bestDist := 0, -1
+	for i, c := range basicRGB {
+		if d := rgbDist(r, g, b, c[0], c[1], c[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return basicColour(best)
+}
+
+// nearest256 picks the closer of the nearest colour-cube entry and the
+// nearest grey-ramp entry. The 16 standard colours are skipped, as
+// terminals commonly redefine them.
+func nearest256(r, g, b uint8) Colour {
+	ci := func(v uint8) int {
+		best := 0
+		for i, l := range cubeLevels {
+			if absDiff(v, l) < absDiff(v, cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	cr, cg, cb := ci(r), ci(g), ci(b)
+	cube := 16 + 36*cr + 6*cg + cb
+	cubeDist := rgbDist(r, g, b, cubeLevels[cr], cubeLevels[cg], cubeLevels[cb])
+
+	avg := (int(r) + int(g) + int(b)) / 3
+	grey := 0
+	if avg > 8 {
+		grey = (avg - 8 + 5) / 10
+	}
+	if grey > 23 {
+		grey = 23
+	}
+	gv := uint8(8 + 10*grey)
+	if rgbDist(r, g, b, gv, gv, gv) < cubeDist {
+		return Colour256(uint8(232 + grey))
+	}
+	return Colour256(uint8(cube))
+}
+
+func rgbDist(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,83 @@
+package prettyconsole
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestColourSGR(t *testing.T) {
+	assert.Equal(t, "", Colour(0).sgr())
+	assert.Equal(t, "\x1b[31m", Red.sgr())
+	assert.Equal(t, "\x1b[90m", BrightBlack.sgr())
+	assert.Equal(t, "\x1b[38;5;244m", Colour256(244).sgr())
+	assert.Equal(t, "\x1b[38;2;1;128;255m", RGB(1, 128, 255).sgr())
+}
+
+func TestColourDownsample(t *testing.T) {
+	tests := []struct {
+		desc  string
+		in    Colour
+		depth ColourDepth
+		want  Colour
+	}{
+		{"basic unchanged", Red, Colours16, Red},
+		{"palette kept at 256", Colour256(208), Colours256, Colour256(208)},
+		{"palette standard index", Colour256(1), Colours16, Red},
+		{"palette bright index", Colour256(12), Colours16, BrightBlue},
+		{"palette cube", Colour256(196), Colours16, BrightRed},
+		{"palette grey", Colour256(244), Colours16, BrightBlack},
+		{"rgb kept at truecolour", RGB(10, 20, 30), TrueColour, RGB(10, 20, 30)},
+		{"rgb to cube", RGB(255, 135, 0), Colours256, Colour256(208)},
+		{"rgb to grey ramp", RGB(128, 128, 128), Colours256, Colour256(244)},
+		{"rgb to black", RGB(0, 0, 0), Colours256, Colour256(16)},
+		{"rgb to basic", RGB(250, 10, 10), Colours16, BrightRed},
+		{"rgb dark green to basic", RGB(0, 190, 0), Colours16, Green},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.in.downsample(tt.depth))
+		})
+	}
+}
+
+func TestDetectColourDepth(t *testing.T) {
+	tests := []struct {
+		colorterm, term string
+		want            ColourDepth
+	}{
+		{"truecolor", "xterm", TrueColour},
+		{"24bit", "", TrueColour},
+		{"", "xterm-direct", TrueColour},
+		{"", "xterm-256color", Colours256},
+		{"", "screen-256color", Colours256},
+		{"", "xterm", Colours16},
+		{"", "", Colours16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.colorterm+"_"+tt.term, func(t *testing.T) {
+			t.Setenv("COLORTERM", tt.colorterm)
+			t.Setenv("TERM", tt.term)
+			assert.Equal(t, tt.want, DetectColourDepth())
+		})
+	}
+}
+
+func TestThemeColourDepth(t *testing.T) {
+	theme := DefaultTheme()
+	theme.Levels[zapcore.InfoLevel] = Style{Colour: RGB(255, 135, 0)}
+	theme.Key = Style{Colour: Colour256(244)}
+	ent := zapcore.Entry{Level: zapcore.InfoLevel, Message: "m"}
+
+	encode := func(depth ColourDepth) string {
+		buf, err := NewEncoder(NewEncoderConfig(), WithTheme(theme), WithColourDepth(depth)).
+			EncodeEntry(ent, nil)
+		assert.NoError(t, err)
+		defer buf.Free()
+		return tagANSI(buf.String())
+	}
+	assert.Contains(t, encode(TrueColour), "<esc:38;2;255;135;0>INF<r>")
+	assert.Contains(t, encode(Colours256), "<esc:38;5;208>INF<r>")
+	assert.Contains(t, encode(Colours16), "<yellow>INF<r>")
+}
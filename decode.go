@@ -0,0 +1,311 @@
+package prettyconsole
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrNotJSONObject is returned when decoding a line that is not a single
+// JSON object, such as plain text interleaved with structured logs.
+var ErrNotJSONObject = errors.New("not a JSON object")
+
+// JSONDecoder maps lines written by zap's JSON encoder back into entries
+// and fields, so they can be re-rendered by this package's encoder.
+type JSONDecoder struct {
+	keys zapcore.EncoderConfig
+}
+
+// NewJSONDecoder returns a decoder for JSON written with the keys (message,
+// level, time, name, caller, function and stacktrace) of cfg. Other
+// settings in cfg are ignored.
+func NewJSONDecoder(cfg zapcore.EncoderConfig) *JSONDecoder {
+	return &JSONDecoder{keys: cfg}
+}
+
+var defaultJSONDecoder = NewJSONDecoder(zap.NewProductionEncoderConfig())
+
+// DecodeJSONEntry decodes a line written with zap's production encoder
+// config. See JSONDecoder.Decode.
+func DecodeJSONEntry(line []byte) (zapcore.Entry, []zapcore.Field, error) {
+	return defaultJSONDecoder.Decode(line)
+}
+
+// Decode maps one line of zap JSON output back into an entry and its
+// fields:
+//
+//   - nested JSON objects become ObjectMarshaler fields, and arrays
+//     ArrayMarshaler fields
+//   - an "error" key, or any key accompanied by a ${key}Verbose or
+//     ${key}Causes key, is reassembled into an error, so causes and
+//     verbose details (such as stacktraces) render like the original error
+//   - numbers become int64 fields when integral and float64 otherwise
+//
+// Entry properties that cannot be decoded, such as an unknown level, are
+// kept as ordinary fields.
+func (d *JSONDecoder) Decode(line []byte) (zapcore.Entry, []zapcore.Field, error) {
+	var ent zapcore.Entry
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return ent, nil, ErrNotJSONObject
+	}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return ent, nil, err
+	}
+	if dec.More() {
+		return ent, nil, ErrNotJSONObject
+	}
+
+	keys := &d.keys
+	if s, ok := takeString(m, keys.MessageKey); ok {
+		ent.Message = s
+	}
+	if s, ok := m[keys.LevelKey].(string); ok && keys.LevelKey != "" {
+		if l, err := ParseLevel(s); err == nil {
+			ent.Level = l
+			delete(m, keys.LevelKey)
+		}
+	}
+	if v, ok := m[keys.TimeKey]; ok && keys.TimeKey != "" {
+		if t, ok := decodeTime(v); ok {
+			ent.Time = t
+			delete(m, keys.TimeKey)
+		}
+	}
+	if s, ok := takeString(m, keys.NameKey); ok {
+		ent.LoggerName = s
+	}
+	if s, ok := m[keys.CallerKey].(string); ok && keys.CallerKey != "" {
+		if i := strings.LastIndexByte(s, ':'); i > 0 {
+			if n, err := strconv.Atoi(s[i+1:]); err == nil {
+				ent.Caller = zapcore.EntryCaller{Defined: true, File: s[:i], Line: n}
+				delete(m, keys.CallerKey)
+			}
+		}
+	}
+	if s, ok := takeString(m, keys.FunctionKey); ok {
+		ent.Caller.Function = s
+	}
+	if s, ok := takeString(m, keys.StacktraceKey); ok {
+		ent.Stack = s
+	}
+
+	fields := make([]zapcore.Field, 0, len(m))
+	for _, k := range errorKeys(m) {
+		fields = append(fields, zap.NamedError(k, takeError(m, k)))
+	}
+	for k, v := range m {
+		// Verbose output without its error is still laid out over
+		// multiple lines: keep that layout rather than escaping it.
+		if s, ok := v.(string); ok && strings.HasSuffix(k, "Verbose") {
+			fields = append(fields, FormattedString(k, s))
+			continue
+		}
+		fields = append(fields, decodeField(k, v))
+	}
+	return ent, fields, nil
+}
+
+// takeString removes and returns the string at key, if there is one.
+func takeString(m map[string]interface{}, key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	s, ok := m[key].(string)
+	if ok {
+		delete(m, key)
+	}
+	return s, ok
+}
+
+// decodeTime accepts the time formats zap's own time encoders write:
+// epoch numbers (in seconds, milliseconds or nanoseconds, told apart by
+// magnitude) and ISO8601/RFC3339 strings.
+func decodeTime(v interface{}) (time.Time, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		// Integers are exact; a float64 is ~256ns off for nanoseconds.
+		if i, err := v.Int64(); err == nil {
+			switch abs := max(i, -i); {
+			case abs >= 1e17:
+				return time.Unix(0, i), true
+			case abs >= 1e11:
+				return time.UnixMilli(i), true
+			default:
+				return time.Unix(i, 0), true
+			}
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch abs := math.Abs(f); {
+		case abs >= 1e17:
+			return time.Unix(0, int64(f)), true
+		case abs >= 1e11:
+			sec, frac := math.Modf(f / 1e3)
+			return time.Unix(int64(sec), int64(frac*1e9)), true
+		default:
+			sec, frac := math.Modf(f)
+			return time.Unix(int64(sec), int64(frac*1e9)), true
+		}
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000Z0700"} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// errorKeys returns the keys of m that zap's error encoding produced, in
+// sorted order so decoding is deterministic.
+func errorKeys(m map[string]interface{}) []string {
+	var keys []string
+	for k, v := range m {
+		if _, ok := v.(string); !ok {
+			continue
+		}
+		_, verbose := m[k+"Verbose"].(string)
+		_, causes := m[k+"Causes"].([]interface{})
+		if k == "error" || verbose || causes {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// takeError removes an error's keys from m and reassembles them into an
+// error. The caller has checked that m[key] is a string.
+func takeError(m map[string]interface{}, key string) error {
+	err := &decodedError{msg: m[key].(string)}
+	delete(m, key)
+	if causes, ok := m[key+"Causes"].([]interface{}); ok {
+		delete(m, key+"Causes")
+		for _, c := range causes {
+			// zap encodes each cause as an object holding an "error" key.
+			if cm, ok := c.(map[string]interface{}); ok {
+				if _, ok := cm["error"].(string); ok {
+					err.causes = append(err.causes, takeError(cm, "error"))
+					continue
+				}
+			}
+			err.causes = append(err.causes, &decodedError{msg: fmt.Sprint(c)})
+		}
+	}
+	if verbose, ok := m[key+"Verbose"].(string); ok {
+		delete(m, key+"Verbose")
+		return &verboseDecodedError{decodedError: *err, verbose: verbose}
+	}
+	return err
+}
+
+// decodedError stands in for an error that was logged as JSON. Its causes
+// are exposed like go.uber.org/multierr's, which is how zap encoded them.
+type decodedError struct {
+	msg    string
+	causes []error
+}
+
+func (e *decodedError) Error() string   { return e.msg }
+func (e *decodedError) Errors() []error { return e.causes }
+
+// verboseDecodedError is a decodedError that was logged with a ${key}Verbose
+// field, which it reproduces for %+v.
+type verboseDecodedError struct {
+	decodedError
+	verbose string
+}
+
+func (e *verboseDecodedError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		_, _ = io.WriteString(s, e.verbose)
+		return
+	}
+	_, _ = io.WriteString(s, e.msg)
+}
+
+func decodeField(key string, v interface{}) zapcore.Field {
+	switch v := v.(type) {
+	case string:
+		return zap.String(key, v)
+	case bool:
+		return zap.Bool(key, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return zap.Int64(key, i)
+		}
+		f, _ := v.Float64()
+		return zap.Float64(key, f)
+	case map[string]interface{}:
+		return zap.Object(key, jsonObject(v))
+	case []interface{}:
+		return zap.Array(key, jsonArray(v))
+	default:
+		return zap.Reflect(key, v)
+	}
+}
+
+// jsonObject renders a decoded JSON object with its keys sorted, as the
+// original field order is lost in decoding.
+type jsonObject map[string]interface{}
+
+func (o jsonObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		decodeField(k, o[k]).AddTo(enc)
+	}
+	return nil
+}
+
+type jsonArray []interface{}
+
+func (a jsonArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range a {
+		switch v := v.(type) {
+		case string:
+			enc.AppendString(v)
+		case bool:
+			enc.AppendBool(v)
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				enc.AppendInt64(i)
+			} else {
+				f, _ := v.Float64()
+				enc.AppendFloat64(f)
+			}
+		case map[string]interface{}:
+			if err := enc.AppendObject(jsonObject(v)); err != nil {
+				return err
+			}
+		case []interface{}:
+			if err := enc.AppendArray(jsonArray(v)); err != nil {
+				return err
+			}
+		default:
+			if err := enc.AppendReflected(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
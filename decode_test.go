@@ -0,0 +1,151 @@
+package prettyconsole
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// jsonLine encodes an entry with zap's production JSON encoder.
+func jsonLine(t *testing.T, ent zapcore.Entry, fields ...zapcore.Field) []byte {
+	t.Helper()
+	buf, err := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()).EncodeEntry(ent, fields)
+	require.NoError(t, err)
+	defer buf.Free()
+	return bytes.Clone(buf.Bytes())
+}
+
+// replay decodes a JSON line and renders it with the pretty encoder.
+func replay(t *testing.T, line []byte) string {
+	t.Helper()
+	ent, fields, err := DecodeJSONEntry(line)
+	require.NoError(t, err)
+	return encodePlain(t, append([]zapcore.Field{zap.String("level", ent.Level.String())}, fields...)...)
+}
+
+func TestDecodeJSONEntry(t *testing.T) {
+	when := time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC)
+	ent, fields, err := DecodeJSONEntry(jsonLine(t, zapcore.Entry{
+		Level:      zapcore.WarnLevel,
+		Time:       when,
+		LoggerName: "svc",
+		Message:    "hello",
+		Caller:     zapcore.NewEntryCaller(0, "/src/pkg/file.go", 42, true),
+		Stack:      "main.main\n\t/src/main.go:1",
+	}, zap.String("s", "v"), zap.Int("i", 1)))
+	require.NoError(t, err)
+
+	assert.Equal(t, zapcore.WarnLevel, ent.Level)
+	assert.True(t, when.Equal(ent.Time), "got %v", ent.Time)
+	assert.Equal(t, "svc", ent.LoggerName)
+	assert.Equal(t, "hello", ent.Message)
+	assert.Equal(t, "pkg/file.go:42", ent.Caller.TrimmedPath())
+	assert.Equal(t, "main.main\n\t/src/main.go:1", ent.Stack)
+	assert.ElementsMatch(t, []zapcore.Field{zap.String("s", "v"), zap.Int64("i", 1)}, fields)
+}
+
+func TestDecodeJSONEntryNotObject(t *testing.T) {
+	for _, line := range []string{"", "plain text", "[1]", "123", `{"a":1} {"b":2}`} {
+		_, _, err := DecodeJSONEntry([]byte(line))
+		assert.ErrorIs(t, err, ErrNotJSONObject, "line %q", line)
+	}
+	_, _, err := DecodeJSONEntry([]byte(`{"unterminated`))
+	assert.Error(t, err)
+}
+
+func TestDecodeJSONEntryUnknownLevel(t *testing.T) {
+	ent, fields, err := DecodeJSONEntry([]byte(`{"level":"loud","msg":"m"}`))
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.InfoLevel, ent.Level)
+	assert.Equal(t, []zapcore.Field{zap.String("level", "loud")}, fields)
+}
+
+// TestDecodeJSONEntryStructures checks decoded objects and arrays render
+// exactly as the originals would have.
+func TestDecodeJSONEntryStructures(t *testing.T) {
+	fields := []zapcore.Field{
+		zap.Object("object", testStableMap{
+			"a": "b",
+			"n": testStableMap{"x": 1, "list": testArray{1, "two", testStableMap{"k": "v"}}},
+		}),
+		zap.Array("array", testArray{testArray{1, 2}, testStableMap{"k": "v"}}),
+	}
+	want := encodePlain(t, append([]zapcore.Field{zap.String("level", "info")}, fields...)...)
+	assert.Equal(t, want, replay(t, jsonLine(t, zapcore.Entry{Message: "msg"}, fields...)))
+}
+
+func TestDecodeJSONEntryErrors(t *testing.T) {
+	t.Run("Causes", func(t *testing.T) {
+		err := multierr.Combine(errors.New("cause 1"), multierr.Combine(errors.New("deep 1"), errors.New("deep 2")))
+		out := replay(t, jsonLine(t, zapcore.Entry{Message: "msg"}, zap.Error(err)))
+		assert.Contains(t, out, "↳ error.cause.0=cause 1\n")
+		assert.Contains(t, out, ".cause.1=deep 1\n")
+		assert.Contains(t, out, ".cause.2=deep 2")
+		assert.NotContains(t, out, "errorCauses")
+	})
+	t.Run("Verbose", func(t *testing.T) {
+		err := pkgerrors.Wrap(errors.New("inner"), "outer")
+		out := replay(t, jsonLine(t, zapcore.Entry{Message: "msg"}, zap.NamedError("failure", err)))
+		assert.Contains(t, out, "↳ failure=outer: inner\n")
+		assert.Contains(t, out, ".detail=inner\n")
+		assert.Contains(t, out, "TestDecodeJSONEntryErrors")
+		assert.NotContains(t, out, "failureVerbose")
+	})
+	t.Run("Plain", func(t *testing.T) {
+		_, fields, err := DecodeJSONEntry(jsonLine(t, zapcore.Entry{}, zap.Error(errors.New("boom"))))
+		require.NoError(t, err)
+		require.Len(t, fields, 1)
+		assert.Equal(t, zapcore.ErrorType, fields[0].Type)
+		assert.EqualError(t, fields[0].Interface.(error), "boom")
+		assert.Equal(t, "boom", fmt.Sprintf("%+v", fields[0].Interface))
+	})
+	t.Run("OrphanVerbose", func(t *testing.T) {
+		out := replay(t, []byte(`{"msg":"m","detailVerbose":"line 1\nline 2"}`))
+		assert.Contains(t, out, "detailVerbose=line 1\n")
+	})
+}
+
+func TestDecodeTime(t *testing.T) {
+	want := time.Date(2024, 1, 15, 14, 30, 45, 500_000_000, time.UTC)
+	for desc, ts := range map[string]string{
+		"seconds": `1705329045.5`,
+		"millis":  `1705329045500`,
+		"nanos":   `1705329045500000000`,
+		"iso8601": `"2024-01-15T14:30:45.500Z"`,
+		"rfc3339": `"2024-01-15T14:30:45.5Z"`,
+	} {
+		t.Run(desc, func(t *testing.T) {
+			ent, fields, err := DecodeJSONEntry([]byte(`{"ts":` + ts + `}`))
+			require.NoError(t, err)
+			assert.Empty(t, fields)
+			assert.True(t, want.Equal(ent.Time), "got %v", ent.Time)
+		})
+	}
+
+	// Nanoseconds that a float64 cannot hold exactly are kept.
+	ent, _, err := DecodeJSONEntry([]byte(`{"ts":1705329045123456789}`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1705329045123456789), ent.Time.UnixNano())
+	ent, _, err = DecodeJSONEntry([]byte(`{"ts":1705329045}`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1705329045), ent.Time.Unix())
+}
+
+func TestJSONDecoderKeys(t *testing.T) {
+	dec := NewJSONDecoder(zapcore.EncoderConfig{MessageKey: "M", LevelKey: "L", FunctionKey: "F"})
+	ent, fields, err := dec.Decode([]byte(`{"M":"m","L":"ERROR","F":"pkg.Func","msg":"kept"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "m", ent.Message)
+	assert.Equal(t, zapcore.ErrorLevel, ent.Level)
+	assert.Equal(t, "pkg.Func", ent.Caller.Function)
+	assert.Equal(t, []zapcore.Field{zap.String("msg", "kept")}, fields)
+}
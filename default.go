@@ -4,8 +4,6 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"runtime/debug"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -14,87 +12,43 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-const (
-	colorBlack = iota + 30
-	colorRed
-	colorGreen
-	colorYellow
-	colorBlue
-	colorMagenta
-	colorCyan
-	colorWhite
-	colorBold     = 1
-	colorDarkGray = 90
-)
-
 func DefaultTimeEncoder(format string) func(time.Time, zapcore.PrimitiveArrayEncoder) {
 	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-		// Fast path for this package's own encoder: write straight into
-		// the entry buffer instead of formatting through an intermediate.
-		if raw, ok := enc.(rawStringAppender); ok {
-			raw.addSeparator()
-			raw.buf.AppendString(ansiDarkGray)
-			raw.buf.AppendTime(t, format)
-			raw.buf.AppendString(ansiReset)
-			raw.inList = true
-			return
-		}
-		buf := _bufferPoolGet()
-		buf.AppendString(ansiDarkGray)
-		buf.AppendTime(t, format)
-		buf.AppendString(ansiReset)
-		enc.AppendString(buf.String())
-		buf.Free()
+		appendTime(enc, false, func(buf *buffer.Buffer) { buf.AppendTime(t, format) })
 	}
 }
 
-const ansiDarkGray = "\x1b[90m"
+// appendTime writes the timestamp write renders in the time style, or the
+// SlowDelta style if slow is set.
+func appendTime(enc zapcore.PrimitiveArrayEncoder, slow bool, write func(*buffer.Buffer)) {
+	// Fast path for this package's own encoder: write straight into the
+	// entry buffer instead of formatting through an intermediate.
+	if raw, ok := enc.(rawStringAppender); ok {
+		raw.addSeparator()
+		raw.buf.AppendString(raw.opts.pal.timeStyle(slow))
+		write(raw.buf)
+		raw.buf.AppendString(raw.opts.pal.reset)
+		raw.inList = true
+		return
+	}
+	buf := _bufferPoolGet()
+	buf.AppendString(defaultOptions.get().pal.timeStyle(slow))
+	write(buf)
+	buf.AppendString(defaultOptions.get().pal.reset)
+	enc.AppendString(buf.String())
+	buf.Free()
+}
 
 func defaultDurationEncoder(dur time.Duration, enc zapcore.PrimitiveArrayEncoder) {
 	enc.AppendString(dur.String())
 }
 
-const defaultColourOffset = 2
-
-var defaultColours = [10][]string{
-	// DIY trace level
-	zapcore.DebugLevel - 1 + defaultColourOffset: {strconv.Itoa(colorDarkGray)},
-	zapcore.DebugLevel + defaultColourOffset:     {strconv.Itoa(colorCyan)},
-	zapcore.InfoLevel + defaultColourOffset:      {strconv.Itoa(colorGreen)},
-	zapcore.WarnLevel + defaultColourOffset:      {strconv.Itoa(colorYellow)},
-	zapcore.ErrorLevel + defaultColourOffset:     {strconv.Itoa(colorRed)},
-	zapcore.FatalLevel + defaultColourOffset:     {strconv.Itoa(colorRed), strconv.Itoa(colorBold)},
-	zapcore.DPanicLevel + defaultColourOffset:    {strconv.Itoa(colorRed), strconv.Itoa(colorBold)},
-	zapcore.PanicLevel + defaultColourOffset:     {strconv.Itoa(colorRed), strconv.Itoa(colorBold)},
-}
-
-// defaultLevelLabels holds the fully coloured label for each known level,
-// so encoding a level is a single precomputed append.
-var defaultLevelLabels = func() (labels [len(defaultColours)]string) {
-	names := map[zapcore.Level]string{
-		zapcore.DebugLevel - 1: "TRC", // DIY trace level
-		zapcore.DebugLevel:     "DBG",
-		zapcore.InfoLevel:      "INF",
-		zapcore.WarnLevel:      "WRN",
-		zapcore.ErrorLevel:     "ERR",
-		zapcore.FatalLevel:     "FTL",
-		zapcore.DPanicLevel:    "DPNC",
-		zapcore.PanicLevel:     "PNC",
-	}
-	for l, name := range names {
-		labels[l+defaultColourOffset] = levelColourPrefixes[l+defaultColourOffset] + name + ansiReset
-	}
-	return labels
-}()
-
-var unknownLevelLabel = levelColourPrefixes[zapcore.PanicLevel+defaultColourOffset] + "???" + ansiReset
-
 func defaultLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
-	if idx := int(l) + defaultColourOffset; idx >= 0 && idx < len(defaultLevelLabels) && defaultLevelLabels[idx] != "" {
-		enc.AppendString(defaultLevelLabels[idx])
-		return
+	pal := &defaultOptions.get().pal
+	if raw, ok := enc.(rawStringAppender); ok {
+		pal = &raw.opts.pal
 	}
-	enc.AppendString(unknownLevelLabel)
+	enc.AppendString(pal.labelFor(l))
 }
 
 var cachedCwd = sync.OnceValues(os.Getwd)
@@ -113,11 +67,19 @@ func defaultCallerEncoder(caller zapcore.EntryCaller, enc zapcore.PrimitiveArray
 		// package paths instead of file paths try trimming the main module
 		// path else this will fall back to the full path
 		str = callerFullPath
-		if buildInfo, ok := debug.ReadBuildInfo(); ok {
-			str = strings.TrimPrefix(str, buildInfo.Main.Path+"/")
+		if m := mainModule(); m != "" {
+			str = strings.TrimPrefix(str, m+"/")
 		}
 	}
 
+	if raw, ok := enc.(rawStringAppender); ok && raw.opts.hyperlinks != "" {
+		raw.addSeparator()
+		raw.buf.AppendString(raw.opts.pal.name)
+		raw.appendLink(caller.File, caller.Line, str)
+		raw.buf.AppendString(raw.opts.pal.reset)
+		raw.inList = true
+		return
+	}
 	appendBold(enc, str)
 }
 
@@ -130,14 +92,16 @@ func defaultNameEncoder(name string, enc zapcore.PrimitiveArrayEncoder) {
 func appendBold(enc zapcore.PrimitiveArrayEncoder, s string) {
 	if raw, ok := enc.(rawStringAppender); ok {
 		raw.addSeparator()
-		raw.buf.AppendString(ansiBold)
+		raw.buf.AppendString(raw.opts.pal.name)
 		raw.buf.AppendString(s)
-		raw.buf.AppendString(ansiReset)
+		raw.buf.AppendString(raw.opts.pal.reset)
 		raw.inList = true
 		return
 	}
 	buf := _bufferPoolGet()
-	colorize(buf, s, strconv.Itoa(colorBold))
+	buf.AppendString(defaultOptions.get().pal.name)
+	buf.AppendString(s)
+	buf.AppendString(defaultOptions.get().pal.reset)
 	enc.AppendString(buf.String())
 	buf.Free()
 }
@@ -153,12 +117,3 @@ type dumpEncoder struct {
 func (d dumpEncoder) Encode(i interface{}) error {
 	return dumpValue(d.w, i)
 }
-
-// colorize returns the string s wrapped in ANSI code c
-func colorize(buf *buffer.Buffer, s string, cols ...string) {
-	for _, col := range cols {
-		buf.AppendString("\x1b[" + col + "m")
-	}
-	buf.AppendString(s)
-	buf.AppendString("\x1b[0m")
-}
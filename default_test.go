@@ -95,7 +95,7 @@ func TestEncoderFallbacks(t *testing.T) {
 	out := stripANSI(buf.String())
 	assert.Contains(t, out, "dur=1m30s")                    // AddDuration falls back to Duration.String
 	assert.Contains(t, out, "durs=[1000000000]")            // AppendDuration falls back to nanoseconds
-	assert.Contains(t, out, "times=[2022-06-19T16:33:42Z]") // AppendTime falls back to RFC3339
+	assert.Contains(t, out, "times=[2022-06-19T16:33:42Z]") // AppendTime never uses EncodeTime
 	assert.Contains(t, out, "A: 1")                         // AddReflected falls back to the reflection dumper
 }
 
@@ -17,14 +17,15 @@ import (
 //
 //   - struct fields (exported and unexported) as "Name: value"
 //   - maps with deterministically sorted keys
-//   - time.Time as RFC3339 and time.Duration in Go's duration syntax
+//   - time.Time as RFC3339 and time.Duration in Go's duration syntax,
+//     unless the encoder options say otherwise
 //   - []byte as a hexdump with offset comments, byte arrays of any size
 //     as compact hex strings
 //   - long scalar lists broken across lines
 //
 // Recursion is bounded: pointer/map/slice cycles render as <cycle> and
-// nesting beyond maxDumpDepth renders as <max depth>, so pathological
-// values can never hang or crash the logger.
+// nesting beyond maxDumpDepth, or the WithMaxDepth bound, renders as
+// <max depth>, so pathological values can never hang or crash the logger.
 
 const (
 	// listBreakLen is the element count above which scalar lists break
@@ -42,6 +43,10 @@ var (
 type dumpState struct {
 	buf   []byte
 	depth int
+	// maxDepth is maxDumpDepth unless the writer sets its own bound.
+	maxDepth int
+	// values is defaultValueFormat unless the writer sets its own.
+	values *valueFormat
 	// visited is a stack of container addresses on the current dump path.
 	// Depth is bounded by maxDumpDepth, so a linear scan beats a map.
 	visited []uintptr
@@ -82,6 +87,16 @@ func dumpValue(w io.Writer, v interface{}) error {
 		dumpPool.Put(d)
 	}()
 
+	d.maxDepth = maxDumpDepth
+	d.values = &defaultValueFormat
+	if iw, ok := w.(indentingWriter); ok {
+		if iw.maxDepth > 0 {
+			d.maxDepth = iw.maxDepth
+		}
+		if iw.values != nil {
+			d.values = iw.values
+		}
+	}
 	if v == nil {
 		d.str("nil")
 	} else {
@@ -105,6 +120,13 @@ func (d *dumpState) str(s string)    { d.buf = append(d.buf, s...) }
 func (d *dumpState) byte_(b byte)    { d.buf = append(d.buf, b) }
 func (d *dumpState) quoted(s string) { d.buf = strconv.AppendQuote(d.buf, s) }
 
+// timeValue writes t quoted, like a string.
+func (d *dumpState) timeValue(t time.Time) {
+	d.byte_('"')
+	d.buf = d.values.appendTime(d.buf, t)
+	d.byte_('"')
+}
+
 // newline starts a fresh line at the current depth.
 func (d *dumpState) newline() {
 	d.byte_('\n')
@@ -124,7 +146,7 @@ func bypass(v reflect.Value) reflect.Value {
 }
 
 func (d *dumpState) value(v reflect.Value) {
-	if d.depth >= maxDumpDepth {
+	if d.depth >= d.maxDepth {
 		d.str("<max depth>")
 		return
 	}
@@ -134,12 +156,12 @@ func (d *dumpState) value(v reflect.Value) {
 	// rendered as timestamps; durations only on the exact type, since any
 	// int64-kind type is convertible to time.Duration.
 	if v.Type() == durationType {
-		d.quoted(time.Duration(v.Int()).String())
+		d.quoted(d.values.duration(time.Duration(v.Int())).String())
 		return
 	}
 	if v.Kind() == reflect.Struct && v.Type().ConvertibleTo(timeType) {
 		if bv := bypass(v); bv.CanInterface() {
-			d.quoted(bv.Convert(timeType).Interface().(time.Time).Format(time.RFC3339))
+			d.timeValue(bv.Convert(timeType).Interface().(time.Time))
 			return
 		}
 	}
@@ -3,6 +3,7 @@ package prettyconsole
 import (
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -10,22 +11,139 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-var _ = zap.RegisterEncoder("pretty_console", func(ec zapcore.EncoderConfig) (zapcore.Encoder, error) {
-	return NewEncoder(ec), nil
-})
+var (
+	_ = zap.RegisterEncoder("pretty_console", func(ec zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return encodingOptions.Load().colour.encoder(ec)
+	})
+	_ = zap.RegisterEncoder("pretty_console_monochrome", func(ec zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return encodingOptions.Load().monochrome.encoder(ec)
+	})
+)
+
+// encodingOptions holds the options of the built-in registered encodings.
+var encodingOptions atomic.Pointer[builtinEncodings]
+
+type builtinEncodings struct {
+	colour, monochrome *registeredOptions
+}
+
+func init() {
+	SetEncodingOptions()
+}
+
+// registeredOptions are the options of a registered encoding. They are
+// resolved once, unless the environment adds to them.
+type registeredOptions struct {
+	opts       []Option
+	monochrome bool
+	resolved   cachedOptions
+}
+
+func newRegisteredOptions(opts []Option, monochrome bool) *registeredOptions {
+	r := &registeredOptions{opts: opts[:len(opts):len(opts)], monochrome: monochrome}
+	r.resolved.build = func() *options { return r.resolve(nil) }
+	return r
+}
+
+func (r *registeredOptions) resolve(env []Option) *options {
+	opts := append(r.opts, env...)
+	if r.monochrome {
+		opts = append(opts, WithColour(false))
+	}
+	return newOptions(opts)
+}
+
+// encoder builds an encoder for a zap.Config, applying the PRETTYCONSOLE_*
+// environment variables (see envPrefix) over the registered options.
+func (r *registeredOptions) encoder(ec zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	env, err := envOptions(&ec)
+	if err != nil {
+		return nil, err
+	}
+	o := r.resolved.get()
+	if len(env) > 0 {
+		o = r.resolve(env)
+	}
+	return newEncoder(ec, o), nil
+}
+
+// SetEncodingOptions sets the options of the "pretty_console" and
+// "pretty_console_monochrome" encodings, so loggers built from a
+// zap.Config - including one loaded from YAML or JSON - can use them.
+// "pretty_console_monochrome" always turns colour off. The options apply
+// to encoders built after the call; existing encoders are unaffected.
+//
+// Registered encodings also read these environment variables, which take
+// precedence over the options and let each developer tune their own
+// output without changing shared configuration:
+//
+//	PRETTYCONSOLE_COLOUR          on or off
+//	PRETTYCONSOLE_THEME           dark or light
+//	PRETTYCONSOLE_<LEVEL>_COLOUR  the style of TRACE, DEBUG, INFO, WARN,
+//	                              ERROR, DPANIC, PANIC, FATAL or a level
+//	                              given to RegisterLevel
+//	PRETTYCONSOLE_TIME_FORMAT     a time layout, a layout name such as
+//	                              Kitchen, RFC3339 or DateTime, or elapsed,
+//	                              delta or dateaware (see ElapsedTimeEncoder,
+//	                              DeltaTimeEncoder and DateAwareTimeEncoder)
+//	PRETTYCONSOLE_LEVEL_STYLE     short (INF), full (INFO), lower (info)
+//	                              or emoji (💡)
+//	PRETTYCONSOLE_QUOTING         never, needed or always (see WithQuoting)
+//	PRETTYCONSOLE_MAX_DEPTH       see WithMaxDepth
+//	PRETTYCONSOLE_MAX_ENTRY_SIZE  see WithMaxEntrySize
+//
+// COLOR is accepted in place of COLOUR. A style is a colour - a name such
+// as red or bright-blue, a 256-colour palette index or a #rrggbb colour -
+// optionally with bold or dim, as in "bold red". Invalid values make
+// building the encoder, and so the logger, fail.
+func SetEncodingOptions(opts ...Option) {
+	encodingOptions.Store(&builtinEncodings{
+		colour:     newRegisteredOptions(opts, false),
+		monochrome: newRegisteredOptions(opts, true),
+	})
+}
+
+// RegisterEncoder registers a pretty console encoding with the given
+// options under name, for use as a zap.Config's Encoding. Like
+// zap.RegisterEncoder, it fails if name is already taken. The encoding
+// reads the same environment variables as those SetEncodingOptions
+// configures.
+func RegisterEncoder(name string, opts ...Option) error {
+	r := newRegisteredOptions(opts, false)
+	return zap.RegisterEncoder(name, func(ec zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return r.encoder(ec)
+	})
+}
 
+// NewConfig returns a development zap.Config logging to stderr. It uses the
+// "pretty_console" encoding when stderr should be coloured (see
+// ColourEnabled), and "pretty_console_monochrome" otherwise.
 func NewConfig() zap.Config {
+	encoding := "pretty_console"
+	if !ColourEnabled(os.Stderr) {
+		encoding = "pretty_console_monochrome"
+	}
 	return zap.Config{
 		Level:            zap.NewAtomicLevelAt(zapcore.DebugLevel),
 		Development:      true,
-		Encoding:         "pretty_console",
+		Encoding:         encoding,
 		EncoderConfig:    NewEncoderConfig(),
 		OutputPaths:      []string{"stderr"},
 		ErrorOutputPaths: []string{"stderr"},
 	}
 }
 
-func NewEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+// NewEncoder creates a pretty console encoder. Options are resolved once,
+// here, so they add no work per entry.
+func NewEncoder(cfg zapcore.EncoderConfig, opts ...Option) zapcore.Encoder {
+	o := defaultOptions.get()
+	if len(opts) > 0 {
+		o = newOptions(opts)
+	}
+	return newEncoder(cfg, o)
+}
+
+func newEncoder(cfg zapcore.EncoderConfig, o *options) zapcore.Encoder {
 	// Like zapcore's encoders, treat an unset line ending as the default:
 	// it is also used internally to lay out namespaces and indents.
 	if cfg.LineEnding == "" {
@@ -34,6 +152,7 @@ func NewEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
 	return &recordingEncoder{e: prettyConsoleEncoder{
 		buf:             nil,
 		cfg:             &cfg,
+		opts:            o,
 		level:           0,
 		namespaceIndent: 0,
 		inList:          false,
@@ -66,9 +185,12 @@ func NewEncoderConfig() zapcore.EncoderConfig {
 	}
 }
 
-func NewLogger(lvl zapcore.Level) *zap.Logger {
+// NewLogger returns a logger writing to stdout, coloured when stdout should
+// be (see ColourEnabled). Options are applied after that check, so
+// WithColour can override it.
+func NewLogger(lvl zapcore.Level, opts ...Option) *zap.Logger {
 	ec := NewEncoderConfig()
-	enc := NewEncoder(ec)
+	enc := NewEncoder(ec, append([]Option{WithColour(ColourEnabled(os.Stdout))}, opts...)...)
 	return zap.New(zapcore.NewCore(
 		enc,
 		os.Stdout,
@@ -83,6 +205,7 @@ type prettyConsoleEncoder struct {
 	buf *buffer.Buffer
 
 	cfg   *zapcore.EncoderConfig
+	opts  *options
 	level zapcore.Level
 
 	namespaceIndent int
@@ -93,6 +216,13 @@ type prettyConsoleEncoder struct {
 	listSepIndent int
 	keyPrefix     string
 
+	// nesting counts the marshalers this encoder is nested inside, offset
+	// is roughly how much of the entry precedes its buffer, and hidden is
+	// how many bytes truncation has cut from it; see limits.go.
+	nesting int
+	offset  int
+	hidden  int
+
 	_listSepComma string
 	_listSepSpace string
 }
@@ -109,6 +239,7 @@ func (e prettyConsoleEncoder) clone() *prettyConsoleEncoder {
 	clone.buf = getBuffer()
 
 	clone.cfg = e.cfg
+	clone.opts = e.opts
 	clone.level = e.level
 
 	clone.namespaceIndent = e.namespaceIndent
@@ -116,6 +247,8 @@ func (e prettyConsoleEncoder) clone() *prettyConsoleEncoder {
 	clone.listSep = e.listSep
 	clone.listSepIndent = e.listSepIndent
 	clone.keyPrefix = e.keyPrefix
+	clone.nesting = e.nesting
+	clone.offset = e.offset
 
 	clone._listSepComma = e._listSepComma
 	clone._listSepSpace = e._listSepSpace
@@ -147,31 +280,33 @@ func (e *prettyConsoleEncoder) encodePreamble(entry zapcore.Entry) {
 	raw := rawStringAppender{e}
 
 	if e.cfg.TimeKey != "" && e.cfg.EncodeTime != nil {
-		e.cfg.EncodeTime(entry.Time, raw)
+		e.safeCall(func() { e.cfg.EncodeTime(entry.Time, raw) })
 	}
 	if e.cfg.LevelKey != "" && e.cfg.EncodeLevel != nil {
-		e.cfg.EncodeLevel(entry.Level, raw)
+		e.safeCall(func() { e.cfg.EncodeLevel(entry.Level, raw) })
 	}
 	if entry.LoggerName != "" && e.cfg.NameKey != "" && e.cfg.EncodeName != nil {
-		e.cfg.EncodeName(entry.LoggerName, raw)
+		e.safeCall(func() { e.cfg.EncodeName(entry.LoggerName, raw) })
 	}
 	if entry.Caller.Defined {
 		if e.cfg.CallerKey != "" && e.cfg.EncodeCaller != nil {
-			e.cfg.EncodeCaller(entry.Caller, raw)
+			e.safeCall(func() { e.cfg.EncodeCaller(entry.Caller, raw) })
 		}
 		if e.cfg.FunctionKey != "" {
 			raw.AppendString(entry.Caller.Function)
 		}
 	}
 	e.addSeparator()
-	e.buf.AppendString(ansiBold)
+	e.buf.AppendString(e.opts.pal.bold)
 	e.colorizeAtLevel(">")
-	e.buf.AppendString(ansiReset)
+	e.buf.AppendString(e.opts.pal.reset)
 	e.inList = true
 
 	if entry.Message != "" && e.cfg.MessageKey != "" {
 		e.addSeparator()
-		e.addSafeString(entry.Message)
+		// Spaces are a message's own; an = is what makes it read as a
+		// field.
+		addStringValue(e, entry.Message, "=")
 		e.inList = true
 	}
 }
@@ -238,14 +373,27 @@ func (e *prettyConsoleEncoder) encodeFields(fields []zapcore.Field) {
 	}
 }
 
-// encodeFinish writes the stacktrace and line ending.
+// encodeFinish writes the source snippet, stacktrace and line ending.
 func (e *prettyConsoleEncoder) encodeFinish(entry zapcore.Entry) {
+	if e.opts.sourceSnippets && entry.Level >= zapcore.ErrorLevel && entry.Caller.Defined {
+		if src, ok := sourceFor(entry.Caller.File, entry.Caller.Line); ok {
+			e.namespaceIndent = 0
+			e.OpenNamespace("")
+			e.namespaceIndent += len("source=")
+			e.keyPrefix = ""
+			e.addSourceSnippet("source", src, entry.Caller.Line)
+		}
+	}
 	if entry.Stack != "" && e.cfg.StacktraceKey != "" {
 		e.namespaceIndent = 0
 		e.OpenNamespace("")
 		e.namespaceIndent += len("stacktrace=")
 		e.keyPrefix = ""
-		e.addIndentedString("stacktrace", strings.TrimPrefix(entry.Stack, "\n"))
+		if frames, ok := parseStack(entry.Stack); ok {
+			e.addStackFrames("stacktrace", frames, 0)
+		} else {
+			e.addIndentedString("stacktrace", strings.TrimPrefix(entry.Stack, "\n"))
+		}
 	}
 	if !e.cfg.SkipLineEnding {
 		e.buf.AppendString(e.cfg.LineEnding)
@@ -259,13 +407,13 @@ func (e *prettyConsoleEncoder) addSeparator() {
 	if e.listSepIndent >= 0 {
 		// Line-break separator: coloured line ending plus indentation,
 		// written without building an intermediate string.
-		e.buf.AppendString(levelColourPrefix(e.level))
+		e.buf.AppendString(e.opts.pal.sep[colourIdx(e.level)])
 		e.buf.AppendString(e.cfg.LineEnding)
 		appendSpaces(e.buf, e.listSepIndent)
-		e.buf.AppendString(ansiReset)
+		e.buf.AppendString(e.opts.pal.reset)
 		return
 	}
-	e.colorizeAtLevel(e.listSep)
+	e.paint(e.opts.pal.sep[colourIdx(e.level)], e.listSep)
 }
 
 // setListSep selects a plain-string separator for the next element.
@@ -294,49 +442,33 @@ func appendSpaces(buf *buffer.Buffer, n int) {
 }
 
 func (e *prettyConsoleEncoder) addKey(key string) {
-	e.colorizeAtLevel(e.keyPrefix + key + "=")
+	e.colorizeKey(e.keyPrefix + key + "=")
+}
+
+// colorizeKey appends s in the key colour for the level we're at.
+func (e *prettyConsoleEncoder) colorizeKey(s string) {
+	e.paint(e.opts.pal.key[colourIdx(e.level)], s)
 }
 
-// colorize returns the string s wrapped in ANSI code c, coloured properly for
-// the logging level we're at.
+// colorizeAtLevel appends s coloured properly for the logging level we're
+// at.
 func (e *prettyConsoleEncoder) colorizeAtLevel(s string) {
-	e.buf.AppendString(levelColourPrefix(e.level))
+	e.paint(e.opts.pal.level[colourIdx(e.level)], s)
+}
+
+// paint appends s wrapped in the given palette prefix and a reset.
+func (e *prettyConsoleEncoder) paint(prefix, s string) {
+	e.buf.AppendString(prefix)
 	e.buf.AppendString(s)
-	e.buf.AppendString(ansiReset)
+	e.buf.AppendString(e.opts.pal.reset)
 }
 
 const (
 	ansiReset = "\x1b[0m"
 	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
 )
 
-// levelColourPrefixes holds the full ANSI prefix for each level, so the
-// hot path appends one precomputed string instead of assembling codes.
-var levelColourPrefixes = func() (p [len(defaultColours)]string) {
-	for i, cols := range defaultColours {
-		for _, c := range cols {
-			p[i] += "\x1b[" + c + "m"
-		}
-	}
-	return p
-}()
-
-// colourIdx maps a level to its colour-table index, treating levels
-// outside the known range like defaultLevelEncoder treats them: as panics.
-// zapcore.Level is an int8, so custom levels must not crash the encoder.
-func colourIdx(l zapcore.Level) int {
-	idx := int(l) + defaultColourOffset
-	if idx < 0 || idx >= len(defaultColours) || defaultColours[idx] == nil {
-		idx = int(zapcore.PanicLevel) + defaultColourOffset
-	}
-	return idx
-}
-
-// levelColourPrefix returns the precomputed ANSI prefix for a level.
-func levelColourPrefix(l zapcore.Level) string {
-	return levelColourPrefixes[colourIdx(l)]
-}
-
 // rawStringAppender will append strings without escaping them,
 type rawStringAppender struct{ *prettyConsoleEncoder }
 
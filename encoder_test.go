@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"errors"
 	"flag"
+	"io"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 	"testing"
@@ -14,69 +14,23 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/thessem/zap-prettyconsole/internal/snapshot"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-// The encoder's raw output is full of ANSI escape sequences, which make
-// expected values impossible to read or diff. Tests therefore compare a
-// tagged form where each escape sequence is replaced by a readable tag.
-// The mapping is bijective, so a tagged comparison is exactly as strict as
-// comparing the raw bytes.
-var ansiTags = []struct{ raw, tag string }{
-	{"\x1b[90m", "<gray>"},
-	{"\x1b[36m", "<cyan>"},
-	{"\x1b[32m", "<green>"},
-	{"\x1b[33m", "<yellow>"},
-	{"\x1b[31m", "<red>"},
-	{"\x1b[1m", "<bold>"},
-	{"\x1b[0m", "<r>"},
-}
-
-var ansiOther = regexp.MustCompile(`\x1b\[([0-9;]*)m`)
-
-// tagANSI converts raw encoder output into the readable tagged form.
-func tagANSI(s string) string {
-	for _, t := range ansiTags {
-		s = strings.ReplaceAll(s, t.raw, t.tag)
-	}
-	// Any sequence without a friendly name keeps its code, so nothing is
-	// ever lost in the round trip.
-	return ansiOther.ReplaceAllString(s, "<esc:$1>")
-}
-
-// stripANSI removes all ANSI escape sequences, for tests that only care
-// about the text content.
-func stripANSI(s string) string {
-	return ansiOther.ReplaceAllString(s, "")
-}
-
-func TestTagANSI(t *testing.T) {
-	assert.Equal(t, "<green>INF<r> plain <bold>x<r> <esc:95>y<r>",
-		tagANSI("\x1b[32mINF\x1b[0m plain \x1b[1mx\x1b[0m \x1b[95my\x1b[0m"))
-	assert.Equal(t, "INF plain x y",
-		stripANSI("\x1b[32mINF\x1b[0m plain \x1b[1mx\x1b[0m \x1b[95my\x1b[0m"))
-}
+// Tests compare output in the tagged form of prettyconsoletest, where each
+// escape sequence is replaced by a readable tag. They use its internal
+// implementation, as prettyconsoletest itself imports this package.
+var (
+	tagANSI      = snapshot.TagANSI
+	stripANSI    = snapshot.StripANSI
+	assertGolden = snapshot.AssertGolden
+)
 
+// update is the -update flag assertGolden looks up to rewrite golden files.
 var update = flag.Bool("update", false, "rewrite golden files with actual test output")
 
-// assertGolden compares got against testdata/<test name>.golden. Running
-// the tests with -update regenerates the files instead, so an intentional
-// output change becomes a reviewable git diff rather than a hand-edit.
-func assertGolden(t *testing.T, got string) {
-	t.Helper()
-	path := filepath.Join("testdata", strings.ReplaceAll(t.Name(), "/", "_")+".golden")
-	if *update {
-		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
-		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
-		return
-	}
-	want, err := os.ReadFile(path)
-	require.NoErrorf(t, err, "missing golden file %s (create it with: go test -run '%s' -update)", path, t.Name())
-	assert.Equalf(t, string(want), got,
-		"output differs from %s (regenerate with: go test -run '%s' -update)", path, t.Name())
-}
-
 // encodePlain encodes a message-only entry with the given fields using a
 // config without time or level, and returns the output stripped of colour
 // codes, so assertions can focus on content.
@@ -92,12 +46,6 @@ func encodePlain(t *testing.T, fields ...zapcore.Field) string {
 	return stripANSI(buf.String())
 }
 
-// rPath removes stacktrace line-numbers from golden comparisons. Remember
-// to manually test with -trimpath. "@" appears in stacktrace paths when the
-// toolchain itself lives in the module cache (e.g.
-// golang.org/toolchain@v0.0.1-go1.21.13.linux-amd64)
-var rPath = regexp.MustCompile(`(github|\/|testing|runtime)[\w\.\\\/\-@]*:\d+`)
-
 // runGoldenCases encodes each entry and compares the tagged output against
 // this test's golden file.
 func runGoldenCases(t *testing.T, tests []goldenCase) {
@@ -107,7 +55,7 @@ func runGoldenCases(t *testing.T, tests []goldenCase) {
 		t.Run(tt.desc, func(t *testing.T) {
 			buf, err := enc.EncodeEntry(tt.ent, tt.fields)
 			if assert.NoError(t, err, "Unexpected encoding error.") {
-				assertGolden(t, tagANSI(rPath.ReplaceAllString(buf.String(), "/<some_file>:<line_number>")))
+				assertGolden(t, tagANSI(snapshot.NormalizeLocations(buf.String())))
 			}
 		})
 	}
@@ -241,6 +189,66 @@ func TestNewConfigBuilds(t *testing.T) {
 	NewLogger(zapcore.InfoLevel).Info("logger smoke test")
 }
 
+// buildToFile builds cfg logging to a temporary file, logs a warning with
+// a short array, and returns what was written.
+func buildToFile(t *testing.T, cfg zap.Config) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "log")
+	cfg.OutputPaths = []string{path}
+	cfg.DisableStacktrace = true
+	cfg.EncoderConfig.TimeKey = zapcore.OmitKey
+	logger, err := cfg.Build()
+	require.NoError(t, err)
+	logger.Warn("m", zap.Array("a", testArray{"aaaaaaaaaa", "bbbbbbbbbb"}))
+	require.NoError(t, logger.Sync())
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestEncodingOptions(t *testing.T) {
+	t.Cleanup(func() { SetEncodingOptions() })
+	cfg := NewConfig()
+
+	cfg.Encoding = "pretty_console_monochrome"
+	assert.Equal(t, "WRN > m\n  ↳ a=[aaaaaaaaaa, bbbbbbbbbb]\n", buildToFile(t, cfg))
+
+	SetEncodingOptions(WithColour(true), WithMaxEntrySize(20))
+	assert.Equal(t, "WRN > m\n  ↳ a=[aaa <truncated 20 bytes>\n", buildToFile(t, cfg),
+		"monochrome keeps colour off")
+
+	cfg.Encoding = "pretty_console"
+	assert.Contains(t, buildToFile(t, cfg), "\x1b[")
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	require.NoError(t, RegisterEncoder("pretty_console_test", WithColour(false), WithMaxDepth(1)))
+	require.Error(t, RegisterEncoder("pretty_console_test"), "names can only be registered once")
+
+	cfg := NewConfig()
+	cfg.Encoding = "pretty_console_test"
+	assert.Equal(t, "WRN > m\n  ↳ a=[aaaaaaaaaa, bbbbbbbbbb]\n", buildToFile(t, cfg))
+}
+
+func TestNewLoggerOptions(t *testing.T) {
+	// Options apply after colour detection, so they can override it. With
+	// colour off the logger writes no escapes, whatever stdout is.
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() { os.Stdout = stdout })
+	t.Setenv("FORCE_COLOR", "1")
+
+	logger := NewLogger(zapcore.InfoLevel, WithColour(false))
+	logger.Info("m")
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "INF > m\n")
+	assert.NotContains(t, string(out), "\x1b")
+}
+
 // TestEncodeEntryDeterministic re-encodes the same entry many times through
 // the same encoder: pooled state leaking between calls would change output.
 func TestEncodeEntryDeterministic(t *testing.T) {
@@ -286,7 +294,7 @@ func TestCloneIndependence(t *testing.T) {
 // copies buffered bytes and isolates further writes from the original.
 func TestInnerEncoderClone(t *testing.T) {
 	cfg := NewEncoderConfig()
-	inner := prettyConsoleEncoder{cfg: &cfg, buf: getBuffer(), listSep: " ", _listSepSpace: " ", _listSepComma: ", "}
+	inner := prettyConsoleEncoder{cfg: &cfg, opts: defaultOptions.get(), buf: getBuffer(), listSep: " ", _listSepSpace: " ", _listSepComma: ", "}
 	inner.buf.AppendString("seed")
 	clone := inner.Clone().(*prettyConsoleEncoder)
 	assert.Equal(t, "seed", clone.buf.String())
@@ -0,0 +1,236 @@
+package prettyconsole
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// envPrefix starts the environment variables registered encodings read;
+// SetEncodingOptions documents them.
+const envPrefix = "PRETTYCONSOLE_"
+
+var colourNames = map[string]Colour{
+	"black":         Black,
+	"red":           Red,
+	"green":         Green,
+	"yellow":        Yellow,
+	"blue":          Blue,
+	"magenta":       Magenta,
+	"cyan":          Cyan,
+	"white":         White,
+	"brightblack":   BrightBlack,
+	"grey":          BrightBlack,
+	"gray":          BrightBlack,
+	"brightred":     BrightRed,
+	"brightgreen":   BrightGreen,
+	"brightyellow":  BrightYellow,
+	"brightblue":    BrightBlue,
+	"brightmagenta": BrightMagenta,
+	"brightcyan":    BrightCyan,
+	"brightwhite":   BrightWhite,
+}
+
+var timeLayouts = map[string]string{
+	"kitchen":     time.Kitchen,
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"datetime":    time.DateTime,
+	"dateonly":    time.DateOnly,
+	"timeonly":    time.TimeOnly,
+	"stamp":       time.Stamp,
+	"stampmilli":  time.StampMilli,
+	"stampmicro":  time.StampMicro,
+	"stampnano":   time.StampNano,
+}
+
+// envOptions returns the options the PRETTYCONSOLE_* environment variables
+// ask for, and sets cfg's time encoder if they ask for a time format. All
+// invalid variables are reported together.
+func envOptions(cfg *zapcore.EncoderConfig) ([]Option, error) {
+	var opts []Option
+	var errs []error
+	invalid := func(name, value, want string) {
+		errs = append(errs, fmt.Errorf("invalid %s%s %q: want %s", envPrefix, name, value, want))
+	}
+
+	if name, v, ok := lookupColourEnv("COLOUR"); ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			opts = append(opts, WithColour(b))
+		} else if b, ok := map[string]bool{"on": true, "off": false}[strings.ToLower(v)]; ok {
+			opts = append(opts, WithColour(b))
+		} else {
+			invalid(name, v, "on or off")
+		}
+	}
+	if v, ok := os.LookupEnv(envPrefix + "THEME"); ok {
+		switch strings.ToLower(v) {
+		case "dark":
+			opts = append(opts, WithTheme(DefaultTheme()))
+		case "light":
+			opts = append(opts, WithTheme(LightTheme()))
+		default:
+			invalid("THEME", v, "dark or light")
+		}
+	}
+	// Any level with a label can be styled, by its full label.
+	registered := registeredLevels()
+	for _, l := range slices.Sorted(maps.Keys(registered)) {
+		name := strings.ToUpper(registered[l].labels.Full)
+		if name == "" {
+			continue
+		}
+		if name, v, ok := lookupColourEnv(name + "_COLOUR"); ok {
+			if s, err := parseStyle(v); err == nil {
+				opts = append(opts, withLevelStyle(l, s))
+			} else {
+				invalid(name, v, err.Error())
+			}
+		}
+	}
+	if v, ok := os.LookupEnv(envPrefix + "TIME_FORMAT"); ok {
+		switch layout, err := parseTimeLayout(v); {
+		case strings.EqualFold(v, "elapsed"):
+			cfg.EncodeTime = ElapsedTimeEncoder(time.Now())
+		case strings.EqualFold(v, "delta"):
+			cfg.EncodeTime = DeltaTimeEncoder(0)
+		case strings.EqualFold(v, "dateaware"):
+			cfg.EncodeTime = DateAwareTimeEncoder(ShowSeconds())
+		case err == nil:
+			cfg.EncodeTime = DefaultTimeEncoder(layout)
+		default:
+			invalid("TIME_FORMAT", v, err.Error())
+		}
+	}
+	if v, ok := os.LookupEnv(envPrefix + "LEVEL_STYLE"); ok {
+		switch strings.ToLower(v) {
+		case "short":
+			opts = append(opts, WithLevelStyle(LevelShort))
+		case "full":
+			opts = append(opts, WithLevelStyle(LevelFull))
+		case "lower":
+			opts = append(opts, WithLevelStyle(LevelLower))
+		case "emoji":
+			opts = append(opts, WithLevelStyle(LevelEmoji))
+		default:
+			invalid("LEVEL_STYLE", v, "short, full, lower or emoji")
+		}
+	}
+	if v, ok := os.LookupEnv(envPrefix + "QUOTING"); ok {
+		switch strings.ToLower(v) {
+		case "never":
+			opts = append(opts, WithQuoting(QuoteNever))
+		case "needed":
+			opts = append(opts, WithQuoting(QuoteWhenNeeded))
+		case "always":
+			opts = append(opts, WithQuoting(QuoteAlways))
+		default:
+			invalid("QUOTING", v, "never, needed or always")
+		}
+	}
+	if v, ok := os.LookupEnv(envPrefix + "MAX_DEPTH"); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			opts = append(opts, WithMaxDepth(n))
+		} else {
+			invalid("MAX_DEPTH", v, "a positive number")
+		}
+	}
+	if v, ok := os.LookupEnv(envPrefix + "MAX_ENTRY_SIZE"); ok {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			opts = append(opts, WithMaxEntrySize(n))
+		} else {
+			invalid("MAX_ENTRY_SIZE", v, "a number of bytes, or 0 for no limit")
+		}
+	}
+	return opts, errors.Join(errs...)
+}
+
+// lookupColourEnv looks up a PRETTYCONSOLE_ variable whose name ends in
+// COLOUR, falling back to the COLOR spelling. It returns the name found,
+// without the prefix.
+func lookupColourEnv(name string) (string, string, bool) {
+	if v, ok := os.LookupEnv(envPrefix + name); ok {
+		return name, v, true
+	}
+	name = strings.TrimSuffix(name, "COLOUR") + "COLOR"
+	v, ok := os.LookupEnv(envPrefix + name)
+	return name, v, ok
+}
+
+// withLevelStyle sets the style of a single level, leaving the rest of the
+// theme alone. The theme's map may belong to the caller, so it is copied.
+func withLevelStyle(l zapcore.Level, s Style) Option {
+	return func(o *options) {
+		o.theme.Levels = maps.Clone(o.theme.Levels)
+		if o.theme.Levels == nil {
+			o.theme.Levels = map[zapcore.Level]Style{}
+		}
+		o.theme.Levels[l] = s
+	}
+}
+
+// parseStyle parses a style such as "bold red", "dim 244" or "#ff8800".
+func parseStyle(s string) (Style, error) {
+	var style Style
+	words := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(words) == 0 {
+		return style, errors.New("a colour")
+	}
+	for _, w := range words {
+		w = strings.ToLower(w)
+		switch {
+		case w == "bold":
+			style.Bold = true
+		case w == "dim":
+			style.Dim = true
+		case style.Colour != 0:
+			return style, errors.New("a single colour")
+		default:
+			c, ok := parseColour(w)
+			if !ok {
+				return style, errors.New("a colour name, 0-255 or #rrggbb, optionally with bold or dim")
+			}
+			style.Colour = c
+		}
+	}
+	return style, nil
+}
+
+func parseColour(s string) (Colour, bool) {
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) != 6 {
+			return 0, false
+		}
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return 0, false
+		}
+		return RGB(uint8(v>>16), uint8(v>>8), uint8(v)), true
+	}
+	if n, err := strconv.ParseUint(s, 10, 8); err == nil {
+		return Colour256(uint8(n)), true
+	}
+	c, ok := colourNames[strings.NewReplacer("-", "", "_", "").Replace(s)]
+	return c, ok
+}
+
+// parseTimeLayout resolves a layout name, or checks that s is a layout by
+// making sure it formats two different times differently.
+func parseTimeLayout(s string) (string, error) {
+	if layout, ok := timeLayouts[strings.ToLower(s)]; ok {
+		return layout, nil
+	}
+	a := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	b := time.Date(1999, 12, 31, 9, 59, 58, 999999999, time.UTC)
+	if s == "" || a.Format(s) == b.Format(s) {
+		return "", errors.New("a time layout or layout name")
+	}
+	return s, nil
+}
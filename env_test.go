@@ -0,0 +1,151 @@
+package prettyconsole
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// encodeRegistered encodes an entry with an encoder built like zap.Config
+// builds the "pretty_console" encoding.
+func encodeRegistered(t *testing.T, fields ...zapcore.Field) (string, error) {
+	t.Helper()
+	cfg := NewEncoderConfig()
+	enc, err := encodingOptions.Load().colour.encoder(cfg)
+	if err != nil {
+		return "", err
+	}
+	buf, err := enc.EncodeEntry(zapcore.Entry{
+		Level:   zapcore.WarnLevel,
+		Time:    time.Date(2024, 3, 4, 15, 4, 5, 0, time.UTC),
+		Message: "m",
+	}, fields)
+	require.NoError(t, err)
+	defer buf.Free()
+	return buf.String(), nil
+}
+
+func TestEnvOptions(t *testing.T) {
+	t.Setenv("PRETTYCONSOLE_COLOUR", "off")
+	out, err := encodeRegistered(t)
+	require.NoError(t, err)
+	assert.Equal(t, "3:04PM WRN > m\n", out)
+
+	t.Setenv("PRETTYCONSOLE_TIME_FORMAT", "DateTime")
+	t.Setenv("PRETTYCONSOLE_LEVEL_STYLE", "full")
+	t.Setenv("PRETTYCONSOLE_MAX_DEPTH", "1")
+	t.Setenv("PRETTYCONSOLE_MAX_ENTRY_SIZE", "0")
+	out, err = encodeRegistered(t,
+		zap.Object("o", testStableMap{"inner": testStableMap{"a": 1}}),
+		zap.Reflect("r", [][]int{{1, 2, 3, 4, 5, 6, 7, 8, 9}}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-04 15:04:05 WARN > m\n"+
+		"  ↳ r=[][]int{\n"+
+		"        <max depth>,\n"+
+		"      }\n"+
+		"  ↳ o.inner=<max depth>\n", out)
+
+	t.Setenv("PRETTYCONSOLE_TIME_FORMAT", "15h04")
+	out, err = encodeRegistered(t)
+	require.NoError(t, err)
+	assert.Equal(t, "15h04 WARN > m\n", out)
+
+	t.Setenv("PRETTYCONSOLE_TIME_FORMAT", "DateAware")
+	out, err = encodeRegistered(t)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-04 3:04:05PM WARN > m\n", out)
+}
+
+func TestEnvColours(t *testing.T) {
+	t.Setenv("PRETTYCONSOLE_COLOR", "1")
+	t.Setenv("PRETTYCONSOLE_THEME", "light")
+	t.Setenv("PRETTYCONSOLE_WARN_COLOUR", "bold #ff8800")
+	t.Setenv("PRETTYCONSOLE_ERROR_COLOR", "bright-blue")
+	t.Cleanup(func() { SetEncodingOptions() })
+	SetEncodingOptions(WithColourDepth(TrueColour))
+
+	out, err := encodeRegistered(t)
+	require.NoError(t, err)
+	assert.Contains(t, out, "\x1b[38;2;255;136;0m\x1b[1mWRN")
+
+	// The level overrides replace only their own level.
+	o := newOptions([]Option{WithTheme(LightTheme()), withLevelStyle(zapcore.WarnLevel, Style{Colour: Red})})
+	assert.Equal(t, Style{Colour: Red}, o.theme.Levels[zapcore.WarnLevel])
+	assert.Equal(t, LightTheme().Levels[zapcore.InfoLevel], o.theme.Levels[zapcore.InfoLevel])
+	assert.Equal(t, Magenta, LightTheme().Levels[zapcore.WarnLevel].Colour, "themes are not modified")
+}
+
+func TestEnvErrors(t *testing.T) {
+	t.Setenv("PRETTYCONSOLE_COLOUR", "sometimes")
+	t.Setenv("PRETTYCONSOLE_THEME", "solarized")
+	t.Setenv("PRETTYCONSOLE_INFO_COLOUR", "red blue")
+	t.Setenv("PRETTYCONSOLE_DEBUG_COLOR", "chartreuse")
+	t.Setenv("PRETTYCONSOLE_TIME_FORMAT", "now")
+	t.Setenv("PRETTYCONSOLE_LEVEL_STYLE", "sparkly")
+	t.Setenv("PRETTYCONSOLE_MAX_DEPTH", "0")
+	t.Setenv("PRETTYCONSOLE_MAX_ENTRY_SIZE", "big")
+
+	_, err := encodeRegistered(t)
+	require.Error(t, err)
+	assert.Equal(t, strings.Join([]string{
+		`invalid PRETTYCONSOLE_COLOUR "sometimes": want on or off`,
+		`invalid PRETTYCONSOLE_THEME "solarized": want dark or light`,
+		`invalid PRETTYCONSOLE_DEBUG_COLOR "chartreuse": want a colour name, 0-255 or #rrggbb, optionally with bold or dim`,
+		`invalid PRETTYCONSOLE_INFO_COLOUR "red blue": want a single colour`,
+		`invalid PRETTYCONSOLE_TIME_FORMAT "now": want a time layout or layout name`,
+		`invalid PRETTYCONSOLE_LEVEL_STYLE "sparkly": want short, full, lower or emoji`,
+		`invalid PRETTYCONSOLE_MAX_DEPTH "0": want a positive number`,
+		`invalid PRETTYCONSOLE_MAX_ENTRY_SIZE "big": want a number of bytes, or 0 for no limit`,
+	}, "\n"), err.Error())
+
+	// The error surfaces when building a logger from a zap.Config.
+	cfg := NewConfig()
+	cfg.Encoding = "pretty_console_monochrome"
+	_, err = cfg.Build()
+	assert.ErrorContains(t, err, "PRETTYCONSOLE_THEME")
+}
+
+func TestParseStyle(t *testing.T) {
+	for in, want := range map[string]Style{
+		"red":            {Colour: Red},
+		"Bright_Green":   {Colour: BrightGreen},
+		"grey":           {Colour: BrightBlack},
+		"bold, 208":      {Colour: Colour256(208), Bold: true},
+		"dim #0A0b0c":    {Colour: RGB(10, 11, 12), Dim: true},
+		"bold":           {Bold: true},
+		" cyan  bold  ":  {Colour: Cyan, Bold: true},
+		"bold dim white": {Colour: White, Bold: true, Dim: true},
+	} {
+		got, err := parseStyle(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "256", "#fff", "#gggggg", "red green", "reddish"} {
+		_, err := parseStyle(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestLevelStyle(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	for style, want := range map[LevelStyle]string{
+		LevelShort:    "DPNC",
+		LevelFull:     "DPANIC",
+		LevelLower:    "dpanic",
+		LevelEmoji:    "💣",
+		LevelStyle(7): "DPNC",
+	} {
+		buf, err := NewEncoder(cfg, WithColour(false), WithLevelStyle(style)).
+			EncodeEntry(zapcore.Entry{Level: zapcore.DPanicLevel, Message: "m"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, want+" > m\n", buf.String())
+		buf.Free()
+	}
+}
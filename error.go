@@ -10,6 +10,7 @@ import (
 	// still commonly attach stacktraces with it, and detecting those requires
 	// its named StackTrace type.
 	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
 )
 
 // trimErrorJoins removes trailing separator characters like ':' or ','
@@ -45,7 +46,14 @@ func trimErrorJoins(s string) string {
 //	    ...
 //	  ],
 //	}
-func (e *prettyConsoleEncoder) encodeError(key string, err error) (retErr error) {
+func (e *prettyConsoleEncoder) encodeError(key string, err error) error {
+	return e.encodeCause(key, err, nil)
+}
+
+// encodeCause encodes err as a cause of an error whose stacktrace (or that
+// of its nearest ancestor with one) is parent. Frames err's stacktrace
+// shares with parent are summarised rather than printed again.
+func (e *prettyConsoleEncoder) encodeCause(key string, err error, parent errors.StackTrace) (retErr error) {
 	enc := e.clone()
 	enc.OpenNamespace(key)
 
@@ -96,11 +104,22 @@ func (e *prettyConsoleEncoder) encodeError(key string, err error) (retErr error)
 	}
 	if basic != "" {
 		enc.namespaceIndent += 1
-		enc.colorizeAtLevel("=")
+		enc.colorizeKey("=")
 		enc.inList = true
 		enc.addSafeString(basic)
 	}
 
+	// Causes are compared against this error's stacktrace, or failing that
+	// the stacktrace this error was compared against.
+	var st errors.StackTrace
+	if stt, ok := err.(interface{ StackTrace() errors.StackTrace }); ok {
+		st = stt.StackTrace()
+	}
+	causeParent := parent
+	if st != nil {
+		causeParent = st
+	}
+
 	// Write causes recursively
 	skipDetail := false
 	for i, ei := range causes {
@@ -112,7 +131,7 @@ func (e *prettyConsoleEncoder) encodeError(key string, err error) (retErr error)
 		} else {
 			key = "cause"
 		}
-		if err := enc.encodeError(key, ei); err != nil {
+		if err := enc.encodeCause(key, ei, causeParent); err != nil {
 			return err
 		}
 		skipDetail = true
@@ -121,10 +140,21 @@ func (e *prettyConsoleEncoder) encodeError(key string, err error) (retErr error)
 	// If there's a stacktrace, print it. If this error is a formatter, we'll
 	// print the detail unless we extracted sub-errors above (as we're probably
 	// just reprinting information we already extracted).
-	if st, ok := err.(interface{ StackTrace() errors.StackTrace }); ok {
+	if st != nil {
+		indent := enc.namespaceIndent
 		enc.OpenNamespace("")
 		enc.namespaceIndent += len("stacktrace=")
-		enc.addIndentedFormat("stacktrace", st.StackTrace())
+		unique, common := trimParentFrames(st, parent)
+		enc.addStackFrames("stacktrace", pkgErrorsFrames(unique), common)
+		if enc.opts.sourceSnippets && enc.level >= zapcore.ErrorLevel && len(unique) > 0 {
+			_, file, line := frameInfo(unique[0])
+			if src, ok := sourceFor(file, line); ok {
+				enc.namespaceIndent = indent
+				enc.OpenNamespace("")
+				enc.namespaceIndent += len("source=")
+				enc.addSourceSnippet("source", src, line)
+			}
+		}
 	} else if ef, ok := err.(fmt.Formatter); ok && !skipDetail {
 		enc.OpenNamespace("")
 		enc.namespaceIndent += len("detail=")
@@ -158,6 +158,12 @@ type indentingWriter struct {
 	buf        io.Writer
 	indent     int
 	lineEnding []byte
+	// maxDepth, if set, bounds the reflection dumper writing through this
+	// writer in place of maxDumpDepth.
+	maxDepth int
+	// values, if set, is how the reflection dumper writes times and
+	// durations in place of defaultValueFormat.
+	values *valueFormat
 }
 
 func (i indentingWriter) Write(p []byte) (n int, err error) {
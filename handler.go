@@ -0,0 +1,226 @@
+package prettyconsole
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// HandlerOptions configures a Handler. The zero value logs at slog.LevelInfo
+// and above with NewEncoderConfig.
+type HandlerOptions struct {
+	// Level reports the minimum level to log. Nil means slog.LevelInfo.
+	Level slog.Leveler
+	// AddSource sets the entry caller from the record's PC. It is rendered
+	// when the encoder config has a CallerKey, which it is given if
+	// EncoderConfig is nil.
+	AddSource bool
+	// EncoderConfig is passed to NewEncoder. Nil means NewEncoderConfig.
+	EncoderConfig *zapcore.EncoderConfig
+	// Options are passed to NewEncoder.
+	Options []Option
+}
+
+// Handler is a slog.Handler rendering records with the pretty console
+// encoder. A record renders byte-identically to logging the equivalent
+// fields through zap: attributes become the zap field zap.Any would choose,
+// groups become namespaces (WithGroup) or objects (slog.Group), and
+// WithAttrs context is kept on the encoder exactly as zap's With does.
+type Handler struct {
+	enc       zapcore.Encoder
+	level     slog.Leveler
+	addSource bool
+	// groups were opened by WithGroup but have no attributes yet. slog
+	// drops empty groups, so they only become namespaces once something
+	// is logged in them.
+	groups []string
+
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler returns a Handler writing to w. opts may be nil.
+func NewHandler(w io.Writer, opts *HandlerOptions) *Handler {
+	if opts == nil {
+		opts = &HandlerOptions{}
+	}
+	var cfg zapcore.EncoderConfig
+	if opts.EncoderConfig != nil {
+		cfg = *opts.EncoderConfig
+	} else {
+		cfg = NewEncoderConfig()
+		if opts.AddSource {
+			cfg.CallerKey = "C"
+		}
+	}
+	if base := cfg.EncodeTime; base != nil {
+		cfg.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			// slog handlers must ignore a zero record time.
+			if !t.IsZero() {
+				base(t, enc)
+			}
+		}
+	}
+	level := opts.Level
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &Handler{
+		enc:       NewEncoder(cfg, opts.Options...),
+		level:     level,
+		addSource: opts.AddSource,
+		mu:        &sync.Mutex{},
+		w:         w,
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level.Level()
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	ent := zapcore.Entry{
+		Level:   slogToZapLevel(r.Level),
+		Time:    r.Time,
+		Message: r.Message,
+	}
+	if h.addSource && r.PC != 0 {
+		f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent.Caller = zapcore.EntryCaller{Defined: true, PC: f.PC, File: f.File, Line: f.Line, Function: f.Function}
+	}
+
+	fields := make([]zapcore.Field, 0, len(h.groups)+r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, a)
+		return true
+	})
+	if len(fields) > 0 && len(h.groups) > 0 {
+		fields = append(groupNamespaces(h.groups), fields...)
+	}
+
+	buf, err := h.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = h.w.Write(buf.Bytes())
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []zapcore.Field
+	for _, a := range attrs {
+		fields = appendAttr(fields, a)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+	clone := *h
+	clone.enc = h.enc.Clone()
+	clone.groups = nil
+	for _, f := range append(groupNamespaces(h.groups), fields...) {
+		f.AddTo(clone.enc)
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+func groupNamespaces(groups []string) []zapcore.Field {
+	fields := make([]zapcore.Field, len(groups))
+	for i, g := range groups {
+		fields[i] = zap.Namespace(g)
+	}
+	return fields
+}
+
+// appendAttr appends the zap field equivalent to a, following slog's
+// rules: LogValuers are resolved, empty attributes and groups are dropped,
+// and groups with no key are inlined.
+func appendAttr(fields []zapcore.Field, a slog.Attr) []zapcore.Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return append(fields, zap.String(a.Key, a.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(a.Key, a.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(a.Key, a.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, a.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(a.Key, a.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(a.Key, a.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(a.Key, a.Value.Time()))
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		if a.Key == "" {
+			for _, ga := range attrs {
+				fields = appendAttr(fields, ga)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(a.Key, slogGroup(attrs)))
+	default:
+		return append(fields, zap.Any(a.Key, a.Value.Any()))
+	}
+}
+
+// slogGroup renders a slog.Group attribute as an object.
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, a := range g {
+		for _, f := range appendAttr(nil, a) {
+			f.AddTo(enc)
+		}
+	}
+	return nil
+}
+
+// slogToZapLevel maps slog levels onto zap's, so records get the same
+// labels and colours. Levels between slog's named levels round down, and
+// anything below slog.LevelDebug, such as SlogTraceLevel, is TraceLevel.
+func slogToZapLevel(l slog.Level) zapcore.Level {
+	switch {
+	case l >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case l >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case l >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	case l >= slog.LevelDebug:
+		return zapcore.DebugLevel
+	default:
+		return TraceLevel
+	}
+}
@@ -0,0 +1,174 @@
+package prettyconsole
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// handlerTestConfig leaves out the time, which would differ between the
+// zap and slog sides of a comparison.
+func handlerTestConfig() *zapcore.EncoderConfig {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = ""
+	return &cfg
+}
+
+// zapOutput logs through a zap logger using the same encoder setup as the
+// handler under test.
+func zapOutput(log func(*zap.Logger)) string {
+	var buf bytes.Buffer
+	logger := zap.New(zapcore.NewCore(NewEncoder(*handlerTestConfig()), zapcore.AddSync(&buf), zapcore.DebugLevel-1))
+	log(logger)
+	return buf.String()
+}
+
+func slogOutput(log func(*slog.Logger)) string {
+	var buf bytes.Buffer
+	log(slog.New(NewHandler(&buf, &HandlerOptions{Level: slog.LevelDebug - 4, EncoderConfig: handlerTestConfig()})))
+	return buf.String()
+}
+
+type testLogValuer struct{ name string }
+
+func (v testLogValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("name", v.name), slog.Int("age", 18))
+}
+
+func TestHandlerMatchesZap(t *testing.T) {
+	when := time.Date(2024, 1, 15, 14, 30, 45, 0, time.UTC)
+	err := errors.New("boom")
+	user := zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("name", "Big Bird")
+		enc.AddInt64("age", 18)
+		return nil
+	})
+
+	tests := []struct {
+		desc string
+		zap  func(*zap.Logger)
+		slog func(*slog.Logger)
+	}{
+		{
+			desc: "Scalars",
+			zap: func(l *zap.Logger) {
+				l.Info("hello", zap.String("s", "v"), zap.Int64("i", -1), zap.Uint64("u", 1),
+					zap.Float64("f", 1.5), zap.Bool("b", true), zap.Duration("d", time.Second),
+					zap.Time("t", when), zap.Any("any", []int{1, 2}), zap.Error(err))
+			},
+			slog: func(l *slog.Logger) {
+				l.Info("hello", "s", "v", "i", -1, slog.Uint64("u", 1),
+					"f", 1.5, "b", true, "d", time.Second,
+					"t", when, "any", []int{1, 2}, "error", err)
+			},
+		},
+		{
+			desc: "Group",
+			zap: func(l *zap.Logger) {
+				l.Warn("m", zap.Object("user", user), zap.String("a", "b"))
+			},
+			slog: func(l *slog.Logger) {
+				l.Warn("m", slog.Group("user", "name", "Big Bird", "age", 18), "a", "b")
+			},
+		},
+		{
+			desc: "InlineAndEmptyGroups",
+			zap: func(l *zap.Logger) {
+				l.Info("m", zap.String("a", "b"), zap.String("c", "d"))
+			},
+			slog: func(l *slog.Logger) {
+				l.Info("m", slog.Group("", "a", "b"), slog.Group("empty"), slog.Attr{}, "c", "d")
+			},
+		},
+		{
+			desc: "LogValuer",
+			zap: func(l *zap.Logger) {
+				l.Info("m", zap.Object("user", user))
+			},
+			slog: func(l *slog.Logger) {
+				l.Info("m", "user", testLogValuer{name: "Big Bird"})
+			},
+		},
+		{
+			desc: "WithAttrsAndGroups",
+			zap: func(l *zap.Logger) {
+				l.With(zap.String("z", "1"), zap.String("a", "2")).
+					With(zap.Namespace("req"), zap.String("id", "x")).
+					Error("m", zap.Namespace("inner"), zap.Int64("n", 1))
+			},
+			slog: func(l *slog.Logger) {
+				l.With("z", "1", "a", "2").
+					WithGroup("req").With("id", "x").
+					WithGroup("inner").
+					Error("m", "n", 1)
+			},
+		},
+		{
+			desc: "EmptyGroupDropped",
+			zap: func(l *zap.Logger) {
+				l.With(zap.String("a", "b")).Info("m")
+			},
+			slog: func(l *slog.Logger) {
+				l.With("a", "b").WithGroup("unused").Info("m")
+			},
+		},
+		{
+			desc: "Levels",
+			zap: func(l *zap.Logger) {
+				l.Log(zapcore.DebugLevel-1, "trace")
+				l.Debug("debug")
+				l.Info("info")
+				l.Warn("warn")
+				l.Error("error")
+			},
+			slog: func(l *slog.Logger) {
+				l.Log(context.Background(), slog.LevelDebug-4, "trace")
+				l.Debug("debug")
+				l.Info("info")
+				l.Log(context.Background(), slog.LevelWarn+2, "warn")
+				l.Log(context.Background(), slog.LevelError+4, "error")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			want := zapOutput(tt.zap)
+			assert.NotEmpty(t, want)
+			assert.Equal(t, tagANSI(want), tagANSI(slogOutput(tt.slog)))
+		})
+	}
+}
+
+func TestHandlerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(NewHandler(&buf, nil))
+	l.Debug("hidden")
+	l.Info("shown")
+	assert.Equal(t, "shown", strings.TrimSpace(stripANSI(buf.String()[strings.Index(buf.String(), ">")+1:])))
+
+	lvl := new(slog.LevelVar)
+	lvl.Set(slog.LevelWarn)
+	h := NewHandler(&buf, &HandlerOptions{Level: lvl})
+	assert.False(t, h.Enabled(context.Background(), slog.LevelInfo))
+	lvl.Set(slog.LevelInfo)
+	assert.True(t, h.Enabled(context.Background(), slog.LevelInfo))
+}
+
+func TestHandlerSourceAndTime(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewHandler(&buf, &HandlerOptions{AddSource: true, Options: []Option{WithColour(false)}})
+	slog.New(h).Info("m")
+	assert.Regexp(t, `^\d{1,2}:\d\d[AP]M INF handler_test\.go:\d+ > m\n$`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, h.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "m", 0)))
+	assert.Equal(t, "INF > m\n", buf.String())
+}
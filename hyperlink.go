@@ -0,0 +1,112 @@
+package prettyconsole
+
+import (
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// URL templates for WithHyperlinks. {abs} is replaced with the absolute
+// path of the file and {line} with the line number.
+const (
+	HyperlinkFile   = "file://{abs}"
+	HyperlinkVSCode = "vscode://file/{abs}:{line}"
+	HyperlinkIDEA   = "idea://open?file={abs}&line={line}"
+)
+
+const (
+	osc8Open  = "\x1b]8;;"
+	osc8Close = "\x1b\\"
+)
+
+// appendLink writes text wrapped in an OSC 8 hyperlink to file:line, or
+// just text when hyperlinks are off. Terminals do not render the link
+// sequences, so they take up no columns and indentation is unaffected.
+func (e *prettyConsoleEncoder) appendLink(file string, line int, text string) {
+	tmpl := e.opts.hyperlinks
+	abs, ok := "", tmpl != "" && file != ""
+	if ok {
+		abs, ok = absSlashPath(file)
+	}
+	if !ok {
+		e.buf.AppendString(text)
+		return
+	}
+	e.buf.AppendString(osc8Open)
+	inQuery := false
+	for {
+		i := strings.IndexByte(tmpl, '{')
+		if i < 0 {
+			e.buf.AppendString(tmpl)
+			break
+		}
+		e.buf.AppendString(tmpl[:i])
+		inQuery = inQuery || strings.IndexByte(tmpl[:i], '?') >= 0
+		tmpl = tmpl[i:]
+		switch {
+		case strings.HasPrefix(tmpl, "{abs}"):
+			// Escaping also keeps control characters in odd file names
+			// from terminating the sequence early.
+			if inQuery {
+				e.buf.AppendString(url.QueryEscape(abs))
+			} else {
+				e.buf.AppendString((&url.URL{Path: abs}).EscapedPath())
+			}
+			tmpl = tmpl[len("{abs}"):]
+		case strings.HasPrefix(tmpl, "{line}"):
+			e.buf.AppendInt(int64(line))
+			tmpl = tmpl[len("{line}"):]
+		default:
+			e.buf.AppendByte('{')
+			tmpl = tmpl[1:]
+		}
+	}
+	e.buf.AppendString(osc8Close)
+	e.buf.AppendString(text)
+	e.buf.AppendString(osc8Open + osc8Close)
+}
+
+// absSlashPath returns file as an absolute, slash-separated path, or false
+// if there is none to link to. Relative paths are resolved against the
+// working directory, as the default caller encoder does. In binaries built
+// with -trimpath they are package paths instead: those in the main module
+// are resolved without the module path, and the rest are not linked, since
+// where they live on disk is unknown.
+func absSlashPath(file string) (string, bool) {
+	if !filepath.IsAbs(file) {
+		if trimmedPaths() {
+			m := mainModule()
+			rel, ok := strings.CutPrefix(filepath.ToSlash(file), m+"/")
+			if m == "" || !ok {
+				return "", false
+			}
+			file = filepath.FromSlash(rel)
+		}
+		cwd, err := cachedCwd()
+		if err != nil {
+			return "", false
+		}
+		file = filepath.Join(cwd, file)
+	}
+	file = filepath.ToSlash(file)
+	if !strings.HasPrefix(file, "/") {
+		// Windows drive paths, as in file:///C:/src/main.go.
+		file = "/" + file
+	}
+	return file, true
+}
+
+// appendLocation writes a "file:line" location, linked when hyperlinks are
+// on and the location parses.
+func (e *prettyConsoleEncoder) appendLocation(loc string) {
+	if e.opts.hyperlinks != "" {
+		if i := strings.LastIndexByte(loc, ':'); i > 0 {
+			if line, err := strconv.Atoi(loc[i+1:]); err == nil {
+				e.appendLink(loc[:i], line, loc)
+				return
+			}
+		}
+	}
+	e.buf.AppendString(loc)
+}
@@ -0,0 +1,118 @@
+package prettyconsole
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// link is the expected OSC 8 hyperlink around text.
+func link(url, text string) string {
+	return osc8Open + url + osc8Close + text + osc8Open + osc8Close
+}
+
+func TestAppendLink(t *testing.T) {
+	for tmpl, want := range map[string]string{
+		HyperlinkFile:           "file:///src/my%20dir/main.go",
+		HyperlinkVSCode:         "vscode://file//src/my%20dir/main.go:12",
+		HyperlinkIDEA:           "idea://open?file=%2Fsrc%2Fmy+dir%2Fmain.go&line=12",
+		"{unknown}{line}{":      "{unknown}12{",
+		"x-{abs}\x1b":           "x-/src/my%20dir/main.go\x1b",
+		"subl://open?url={abs}": "subl://open?url=%2Fsrc%2Fmy+dir%2Fmain.go",
+	} {
+		enc := prettyConsoleEncoder{buf: getBuffer(), opts: newOptions([]Option{WithHyperlinks(tmpl)})}
+		enc.appendLink("/src/my dir/main.go", 12, "main.go:12")
+		assert.Equal(t, link(want, "main.go:12"), enc.buf.String(), "template %q", tmpl)
+		putBuffer(enc.buf)
+	}
+
+	enc := prettyConsoleEncoder{buf: getBuffer(), opts: newOptions([]Option{WithHyperlinks(HyperlinkFile)})}
+	enc.appendLocation("no line")
+	enc.appendLocation("/a.go:x")
+	enc.appendLocation("/a.go:7")
+	assert.Equal(t, "no line/a.go:x"+link("file:///a.go", "/a.go:7"), enc.buf.String())
+	putBuffer(enc.buf)
+
+	// Relative paths are resolved against the working directory, and
+	// query parameters escape &, ? and #.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	wd = filepath.ToSlash(wd)
+	enc = prettyConsoleEncoder{buf: getBuffer(), opts: newOptions([]Option{WithHyperlinks(HyperlinkFile)})}
+	enc.appendLink("pkg/main.go", 3, "pkg/main.go:3")
+	assert.Equal(t, link("file://"+(&url.URL{Path: wd}).EscapedPath()+"/pkg/main.go", "pkg/main.go:3"), enc.buf.String())
+	putBuffer(enc.buf)
+
+	// With -trimpath, main module paths are resolved the same way and
+	// other packages are left unlinked.
+	trimmed := trimmedPaths
+	trimmedPaths = func() bool { return true }
+	t.Cleanup(func() { trimmedPaths = trimmed })
+	enc = prettyConsoleEncoder{buf: getBuffer(), opts: newOptions([]Option{WithHyperlinks(HyperlinkFile)})}
+	enc.appendLink(mainModule()+"/pkg/main.go", 3, "pkg/main.go:3")
+	enc.appendLink("github.com/pkg/errors/errors.go", 3, "errors.go:3")
+	enc.appendLink("runtime/proc.go", 3, "proc.go:3")
+	assert.Equal(t, link("file://"+(&url.URL{Path: wd}).EscapedPath()+"/pkg/main.go", "pkg/main.go:3")+
+		"errors.go:3proc.go:3", enc.buf.String())
+	putBuffer(enc.buf)
+	trimmedPaths = trimmed
+
+	enc = prettyConsoleEncoder{buf: getBuffer(), opts: newOptions([]Option{WithHyperlinks(HyperlinkIDEA)})}
+	enc.appendLink("/src/a&b?c#d.go", 3, "x")
+	assert.Equal(t, link("idea://open?file=%2Fsrc%2Fa%26b%3Fc%23d.go&line=3", "x"), enc.buf.String())
+	putBuffer(enc.buf)
+}
+
+func TestHyperlinks(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	cfg.CallerKey = "C"
+	ent := zapcore.Entry{
+		Message: "m",
+		Caller:  zapcore.NewEntryCaller(0, "/src/pkg/file.go", 42, true),
+		Stack:   "main.main\n\t/src/main.go:10\nruntime.main\n\t/go/src/runtime/proc.go:283",
+	}
+
+	buf, err := NewEncoder(cfg, WithHyperlinks(HyperlinkVSCode)).EncodeEntry(ent, nil)
+	require.NoError(t, err)
+	got := buf.String()
+	buf.Free()
+	assert.Regexp(t, `\x1b\[1m`+regexp.QuoteMeta(osc8Open+"vscode://file//src/pkg/file.go:42"+osc8Close)+
+		`[./]*src/pkg/file\.go:42`+regexp.QuoteMeta(osc8Open+osc8Close)+`\x1b\[0m`, got)
+	assert.Contains(t, got, "stacktrace=\x1b[0mmain.main "+link("vscode://file//src/main.go:10", "/src/main.go:10")+"\n")
+	assert.Contains(t, got, "\n               \x1b[2mruntime.main "+link("vscode://file//go/src/runtime/proc.go:283", "/go/src/runtime/proc.go:283"))
+
+	// The links add no visible columns, so the layout is unchanged.
+	plain, err := NewEncoder(cfg).EncodeEntry(ent, nil)
+	require.NoError(t, err)
+	defer plain.Free()
+	assert.Equal(t, stripANSI(plain.String()), stripANSI(stripLinks(got)))
+
+	// Without colour there are no escape sequences at all.
+	mono, err := NewEncoder(cfg, WithHyperlinks(HyperlinkFile), WithColour(false)).EncodeEntry(ent, nil)
+	require.NoError(t, err)
+	defer mono.Free()
+	assert.NotContains(t, mono.String(), "\x1b")
+}
+
+// stripLinks removes OSC 8 hyperlink sequences, keeping their text.
+func stripLinks(s string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, osc8Open)
+		if i < 0 {
+			return b.String() + s
+		}
+		b.WriteString(s[:i])
+		s = s[i+len(osc8Open):]
+		j := strings.Index(s, osc8Close)
+		s = s[j+len(osc8Close):]
+	}
+}
@@ -0,0 +1,120 @@
+// Package snapshot implements the helpers prettyconsoletest exports. They
+// live here so the prettyconsole package's own tests can use them:
+// prettyconsoletest imports prettyconsole, so those tests cannot import it
+// back.
+package snapshot
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// UpdateEnv is the environment variable that, set to a true value such as
+// 1, makes AssertGolden rewrite golden files instead of comparing against
+// them.
+const UpdateEnv = "PRETTYCONSOLE_UPDATE_GOLDEN"
+
+// updating reports whether golden files should be rewritten: when UpdateEnv
+// is true, or when the test binary has a boolean -update flag that is set.
+// This package registers no flag itself, so it cannot clash with one the
+// importing package defines.
+func updating() bool {
+	if b, err := strconv.ParseBool(os.Getenv(UpdateEnv)); err == nil {
+		return b
+	}
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	g, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	b, _ := g.Get().(bool)
+	return b
+}
+
+var ansiTags = []struct{ raw, tag string }{
+	{"\x1b[90m", "<gray>"},
+	{"\x1b[36m", "<cyan>"},
+	{"\x1b[32m", "<green>"},
+	{"\x1b[33m", "<yellow>"},
+	{"\x1b[31m", "<red>"},
+	{"\x1b[1m", "<bold>"},
+	{"\x1b[0m", "<r>"},
+}
+
+var (
+	ansiOther = regexp.MustCompile(`\x1b\[([0-9;]*)m`)
+	tagOther  = regexp.MustCompile(`<esc:([0-9;]*)>`)
+	// hyperlinks matches OSC 8 sequences, ended by ST or BEL.
+	hyperlinks = regexp.MustCompile(`\x1b\]8;[^\x07\x1b]*(?:\x1b\\|\x07)`)
+)
+
+// TagANSI replaces the ANSI escape sequences in s with readable tags: the
+// colours of the default theme by name, such as <red>, bold as <bold> and
+// reset as <r>. Any other colour sequence keeps its code, as in
+// <esc:38;5;208>. Tags are not escaped, so text in s that already reads
+// like a tag, such as a logged "<red>", is indistinguishable from the
+// sequence. OSC 8 hyperlinks, from WithHyperlinks, are left as they are.
+func TagANSI(s string) string {
+	for _, t := range ansiTags {
+		s = strings.ReplaceAll(s, t.raw, t.tag)
+	}
+	return ansiOther.ReplaceAllString(s, "<esc:$1>")
+}
+
+// UntagANSI turns the tags TagANSI writes back into escape sequences. Text
+// that only looked like a tag before tagging is turned into one too.
+func UntagANSI(s string) string {
+	for _, t := range ansiTags {
+		s = strings.ReplaceAll(s, t.tag, t.raw)
+	}
+	return tagOther.ReplaceAllString(s, "\x1b[${1}m")
+}
+
+// StripANSI removes ANSI colour sequences and OSC 8 hyperlinks, keeping
+// the linked text, for tests that only care about the text content.
+func StripANSI(s string) string {
+	return ansiOther.ReplaceAllString(hyperlinks.ReplaceAllString(s, ""), "")
+}
+
+// locations matches file:line locations in callers and stacktraces. "@"
+// appears in stacktrace paths when the toolchain itself lives in the
+// module cache (e.g. golang.org/toolchain@v0.0.1-go1.21.13.linux-amd64).
+var locations = regexp.MustCompile(`(github|\/|testing|runtime)[\w\.\\\/\-@]*:\d+`)
+
+// NormalizeLocations replaces file:line locations, such as those in
+// callers and stacktraces, with "/<some_file>:<line_number>", so golden
+// files survive unrelated edits and different checkouts. Remember to also
+// check by hand with -trimpath.
+func NormalizeLocations(s string) string {
+	return locations.ReplaceAllString(s, "/<some_file>:<line_number>")
+}
+
+// AssertGolden compares got against testdata/<test name>.golden, with
+// subtest separators replaced by underscores. Running the tests with
+// PRETTYCONSOLE_UPDATE_GOLDEN=1, or with -update if the test package
+// defines that flag, writes got to the file instead, so an intentional
+// output change becomes a reviewable diff rather than a hand-edit.
+func AssertGolden(t testing.TB, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", strings.ReplaceAll(t.Name(), "/", "_")+".golden")
+	if updating() {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.NoErrorf(t, err, "missing golden file %s (create it with: %s=1 go test -run '%s')", path, UpdateEnv, t.Name())
+	assert.Equalf(t, string(want), got,
+		"output differs from %s (regenerate with: %s=1 go test -run '%s')", path, UpdateEnv, t.Name())
+}
@@ -0,0 +1,31 @@
+package snapshot
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update is read by AssertGolden through flag.Lookup, the way the
+// prettyconsole package's tests define it.
+var update = flag.Bool("update", false, "rewrite golden files with actual test output")
+
+func TestUpdating(t *testing.T) {
+	prev := *update
+	t.Cleanup(func() { *update = prev })
+	t.Setenv(UpdateEnv, "")
+
+	*update = false
+	assert.False(t, updating())
+	require.NoError(t, flag.Set("update", "true"))
+	assert.True(t, updating())
+
+	// The environment variable, when set, wins over the flag.
+	t.Setenv(UpdateEnv, "0")
+	assert.False(t, updating())
+	*update = false
+	t.Setenv(UpdateEnv, "true")
+	assert.True(t, updating())
+}
@@ -0,0 +1,114 @@
+package prettyconsole
+
+import (
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelStyle selects how level labels are written.
+type LevelStyle int
+
+const (
+	// LevelShort writes three letter labels such as INF and WRN.
+	LevelShort LevelStyle = iota
+	// LevelFull writes labels in full, such as INFO and WARN.
+	LevelFull
+	// LevelLower writes labels in full and in lower case, such as info
+	// and warn.
+	LevelLower
+	// LevelEmoji writes a single emoji, such as 💡 and 🚧.
+	LevelEmoji
+)
+
+// LevelLabels are the labels of a level in each LevelStyle. Unset labels
+// fall back: Short and Full to each other, and Emoji to Short. LevelLower
+// writes Full in lower case.
+type LevelLabels struct {
+	Short string
+	Full  string
+	Emoji string
+}
+
+// label returns the label for a style.
+func (l LevelLabels) label(s LevelStyle) string {
+	short, full := l.Short, l.Full
+	if short == "" {
+		short = full
+	}
+	if full == "" {
+		full = short
+	}
+	switch s {
+	case LevelFull:
+		return full
+	case LevelLower:
+		return strings.ToLower(full)
+	case LevelEmoji:
+		if l.Emoji != "" {
+			return l.Emoji
+		}
+	}
+	return short
+}
+
+type levelInfo struct {
+	labels LevelLabels
+	// style is used when the theme has no style for the level.
+	style Style
+}
+
+// levels is the registry of level labels, compiled into each encoder's
+// palette when it is built. Its generation counts registrations, so
+// cached options know to rebuild.
+var levels = struct {
+	sync.RWMutex
+	m   map[zapcore.Level]levelInfo
+	gen atomic.Uint64
+}{m: map[zapcore.Level]levelInfo{
+	TraceLevel:          {labels: LevelLabels{"TRC", "TRACE", "🔬"}},
+	zapcore.DebugLevel:  {labels: LevelLabels{"DBG", "DEBUG", "🐛"}},
+	zapcore.InfoLevel:   {labels: LevelLabels{"INF", "INFO", "💡"}},
+	zapcore.WarnLevel:   {labels: LevelLabels{"WRN", "WARN", "🚧"}},
+	zapcore.ErrorLevel:  {labels: LevelLabels{"ERR", "ERROR", "🔥"}},
+	zapcore.DPanicLevel: {labels: LevelLabels{"DPNC", "DPANIC", "💣"}},
+	zapcore.PanicLevel:  {labels: LevelLabels{"PNC", "PANIC", "💥"}},
+	zapcore.FatalLevel:  {labels: LevelLabels{"FTL", "FATAL", "💀"}},
+}}
+
+// RegisterLevel gives a custom level, such as a NOTICE or AUDIT level
+// between zap's own, labels to be written with and a style to use when
+// the theme has none for it. Registering one of zap's levels replaces its
+// labels. A registered level's labels are always written; a zero style,
+// with no style for the level in the theme either, takes PanicLevel's.
+// Without registration, custom levels are written as ??? in the style of
+// PanicLevel. Give each level labels of its own: ParseLevel reads
+// a label shared by several levels as the lowest of them.
+//
+// Encoders compile the labels when they are built, so register levels
+// first, such as from an init function.
+func RegisterLevel(l zapcore.Level, labels LevelLabels, style Style) {
+	levels.Lock()
+	defer levels.Unlock()
+	m := maps.Clone(levels.m)
+	m[l] = levelInfo{labels: labels, style: style}
+	levels.m = m
+	levels.gen.Add(1)
+}
+
+// registeredLevels returns the registry. The map is replaced, never
+// changed, on registration, so it can be read without the lock.
+func registeredLevels() map[zapcore.Level]levelInfo {
+	levels.RLock()
+	defer levels.RUnlock()
+	return levels.m
+}
+
+// labelPadding returns the spaces padding a label to width terminal
+// columns, as measured by displayWidth.
+func labelPadding(s string, width int) string {
+	return strings.Repeat(" ", max(width-displayWidth(s), 0))
+}
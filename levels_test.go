@@ -0,0 +1,170 @@
+package prettyconsole
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+const auditLevel = zapcore.Level(6)
+
+// registerTestLevel registers auditLevel until the test ends.
+func registerTestLevel(t *testing.T, labels LevelLabels, style Style) {
+	t.Helper()
+	saved := registeredLevels()
+	t.Cleanup(func() {
+		levels.Lock()
+		levels.m = saved
+		levels.Unlock()
+		levels.gen.Add(1)
+	})
+	RegisterLevel(auditLevel, labels, style)
+}
+
+func encodeLevel(t *testing.T, enc zapcore.Encoder, l zapcore.Level) string {
+	t.Helper()
+	buf, err := enc.EncodeEntry(zapcore.Entry{Level: l, Message: "m"}, nil)
+	require.NoError(t, err)
+	defer buf.Free()
+	return tagANSI(buf.String())
+}
+
+func TestRegisterLevel(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	// Built before registering, and so without the level.
+	before := NewEncoder(cfg)
+	assert.True(t, strings.HasPrefix(encodeLevel(t, before, auditLevel), "<red><bold>???<r>"))
+
+	registerTestLevel(t, LevelLabels{Full: "Audit", Emoji: "📝"}, Style{Colour: Blue})
+	assert.Equal(t, "<esc:34>Audit<r><esc:34> <r><bold><esc:34>><r><r><esc:34> <r>m\n", encodeLevel(t, NewEncoder(cfg), auditLevel))
+	assert.True(t, strings.HasPrefix(encodeLevel(t, before, auditLevel), "<red><bold>???<r>"))
+
+	for style, want := range map[LevelStyle]string{
+		LevelShort: "Audit > m\n",
+		LevelFull:  "Audit > m\n",
+		LevelLower: "audit > m\n",
+		LevelEmoji: "📝 > m\n",
+	} {
+		assert.Equal(t, want, encodeLevel(t, NewEncoder(cfg, WithColour(false), WithLevelStyle(style)), auditLevel))
+	}
+
+	// The theme's style wins over the registered one.
+	theme := DefaultTheme()
+	theme.Levels[auditLevel] = Style{Colour: Magenta}
+	assert.Contains(t, encodeLevel(t, NewEncoder(cfg, WithTheme(theme)), auditLevel), "<esc:35>Audit<r>")
+}
+
+func TestRegisterLevelWithoutStyle(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	registerTestLevel(t, LevelLabels{Short: "NTC"}, Style{})
+	assert.True(t, strings.HasPrefix(encodeLevel(t, NewEncoder(cfg), auditLevel), "<red><bold>NTC<r>"),
+		"a zero style takes PanicLevel's")
+
+	// Full falls back to Short, as Short does to Full.
+	for style, want := range map[LevelStyle]string{
+		LevelShort: "NTC > m\n",
+		LevelFull:  "NTC > m\n",
+		LevelLower: "ntc > m\n",
+		LevelEmoji: "NTC > m\n",
+	} {
+		assert.Equal(t, want, encodeLevel(t, NewEncoder(cfg, WithColour(false), WithLevelStyle(style)), auditLevel))
+	}
+	l, err := ParseLevel("ntc")
+	require.NoError(t, err)
+	assert.Equal(t, auditLevel, l)
+	assert.Equal(t, "ntc", NewAtomicLevelAt(auditLevel).String())
+}
+
+func TestRegisterLevelReplacesLabels(t *testing.T) {
+	saved := registeredLevels()
+	t.Cleanup(func() {
+		levels.Lock()
+		levels.m = saved
+		levels.Unlock()
+		levels.gen.Add(1)
+	})
+	RegisterLevel(zapcore.InfoLevel, LevelLabels{Short: "NFO", Full: "NOTICE"}, Style{})
+
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	assert.Equal(t, "NFO > m\n", encodeLevel(t, NewEncoder(cfg, WithColour(false)), zapcore.InfoLevel))
+	// With no registered style, zap's levels keep the theme's.
+	assert.Contains(t, encodeLevel(t, NewEncoder(cfg), zapcore.InfoLevel), "<green>NFO<r>")
+}
+
+func TestRegisteredLevelInRegisteredEncoding(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	registered := func() zapcore.Encoder {
+		enc, err := encodingOptions.Load().monochrome.encoder(cfg)
+		require.NoError(t, err)
+		return enc
+	}
+	assert.Equal(t, "??? > m\n", encodeLevel(t, registered(), auditLevel))
+
+	// The cached options are rebuilt with the new level.
+	registerTestLevel(t, LevelLabels{Short: "AUD", Full: "AUDIT"}, Style{Colour: Blue})
+	assert.Equal(t, "AUD > m\n", encodeLevel(t, registered(), auditLevel))
+
+	t.Setenv("PRETTYCONSOLE_LEVEL_STYLE", "full")
+	assert.Equal(t, "AUDIT > m\n", encodeLevel(t, registered(), auditLevel))
+
+	// Registered levels are styled by their full label.
+	t.Setenv("PRETTYCONSOLE_AUDIT_COLOUR", "red")
+	enc, err := encodingOptions.Load().colour.encoder(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, encodeLevel(t, enc, auditLevel), "<red>AUDIT<r>")
+}
+
+func TestLevelWidth(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	for _, tt := range []struct {
+		style LevelStyle
+		level zapcore.Level
+		want  string
+	}{
+		{LevelFull, zapcore.InfoLevel, "INFO   > m\n"},
+		{LevelFull, zapcore.DPanicLevel, "DPANIC > m\n"},
+		{LevelFull, zapcore.Level(42), "???    > m\n"},
+		{LevelEmoji, zapcore.InfoLevel, "💡     > m\n"},
+	} {
+		enc := NewEncoder(cfg, WithColour(false), WithLevelStyle(tt.style), WithLevelWidth(6))
+		assert.Equal(t, tt.want, encodeLevel(t, enc, tt.level))
+	}
+
+	// Padding goes outside the colour.
+	assert.Contains(t, encodeLevel(t, NewEncoder(cfg, WithLevelWidth(4)), zapcore.InfoLevel), "<green>INF<r> ")
+
+	// Symbols drawn as emoji are padded by their width on screen.
+	registerTestLevel(t, LevelLabels{Full: "Audit", Emoji: "⚠\ufe0f"}, Style{Colour: Blue})
+	enc := NewEncoder(cfg, WithColour(false), WithLevelStyle(LevelEmoji), WithLevelWidth(3))
+	assert.Equal(t, "⚠\ufe0f  > m\n", encodeLevel(t, enc, auditLevel))
+}
+
+func TestCustomLevelContext(t *testing.T) {
+	// Levels outside the cached range render their context each time.
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	enc := NewEncoder(cfg, WithColour(false)).(*recordingEncoder).Clone()
+	enc.AddString("k", "v")
+	for range 2 {
+		for _, l := range []zapcore.Level{-100, zapcore.InfoLevel, 100} {
+			assert.Contains(t, encodeLevel(t, enc, l), "> m k=v\n")
+		}
+	}
+}
+
+func TestLevelLabelsFallback(t *testing.T) {
+	l := LevelLabels{Full: "NOTICE"}
+	assert.Equal(t, "NOTICE", l.label(LevelShort))
+	assert.Equal(t, "notice", l.label(LevelLower))
+	assert.Equal(t, "NOTICE", l.label(LevelEmoji))
+	l.Short = "NTC"
+	assert.Equal(t, "NTC", l.label(LevelEmoji))
+}
@@ -0,0 +1,114 @@
+package prettyconsole
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// Nested marshalers are bounded like the reflection dumper: nesting beyond
+// the maximum depth renders as <max depth>, and output past the maximum
+// entry size is cut and marked <truncated N bytes>.
+
+// nest returns a clone of e for encoding a nested marshaler.
+func (e *prettyConsoleEncoder) nest() *prettyConsoleEncoder {
+	enc := e.clone()
+	enc.nesting++
+	enc.offset += e.buf.Len()
+	enc.hidden = 0
+	return enc
+}
+
+// unnest writes out and releases a clone returned by nest.
+func (e *prettyConsoleEncoder) unnest(enc *prettyConsoleEncoder) {
+	_, _ = e.buf.Write(enc.buf.Bytes())
+	e.hidden += enc.hidden
+	putPrettyConsoleEncoder(enc)
+}
+
+// tooDeep reports whether e is nested beyond the maximum depth.
+func (e *prettyConsoleEncoder) tooDeep() bool {
+	return e.nesting > e.opts.maxDepth
+}
+
+// addMarker writes prefix, in the key colour, followed by s.
+func (e *prettyConsoleEncoder) addMarker(prefix, s string) {
+	if prefix != "" {
+		e.colorizeKey(prefix)
+	}
+	e.addSafeString(s)
+}
+
+// cutTo discards everything written from l on. The encoder writes into its
+// own buffer, so this copies rather than truncates.
+func (e *prettyConsoleEncoder) cutTo(l int) {
+	buf := getBuffer()
+	_, _ = buf.Write(e.buf.Bytes()[:l])
+	putBuffer(e.buf)
+	e.buf = buf
+}
+
+// truncate cuts the output of a nested marshaler, which began at start,
+// back to the maximum entry size. If nothing past start fits, the marker
+// follows prefix instead. Markers from nested truncations always fall in
+// the cut, so they are folded into one marker counting all the bytes cut.
+func (e *prettyConsoleEncoder) truncate(start int, prefix string) {
+	limit := e.opts.maxEntrySize
+	if limit == 0 || e.offset+e.buf.Len() <= limit {
+		return
+	}
+	b := e.buf.Bytes()
+	cut := safeCut(b, max(start, limit-e.offset), start)
+	dropped := len(b) - cut + e.hidden
+	e.cutTo(cut)
+
+	if cut == start {
+		if prefix != "" {
+			e.colorizeKey(prefix)
+		}
+	} else {
+		// The cut may fall inside a colour or a hyperlink; end both.
+		e.buf.AppendString(e.opts.pal.reset)
+		if e.opts.hyperlinks != "" {
+			e.buf.AppendString(osc8Open + osc8Close)
+		}
+		e.buf.AppendByte(' ')
+	}
+	e.buf.AppendString("<truncated ")
+	e.buf.AppendInt(int64(dropped))
+	e.buf.AppendString(" bytes>")
+	e.hidden = dropped - (e.buf.Len() - cut)
+}
+
+// safeCut moves the cut point n back, but not before floor, until it
+// splits neither a UTF-8 sequence nor an escape sequence.
+func safeCut(b []byte, n, floor int) int {
+	for n > floor && !utf8.RuneStart(b[n]) {
+		n--
+	}
+	if i := bytes.LastIndexByte(b[floor:n], '\x1b'); i >= 0 && !escapeDone(b[floor+i:n]) {
+		n = floor + i
+	}
+	return n
+}
+
+// escapeDone reports whether seq, which starts with ESC, holds a whole
+// escape sequence. Only the CSI and string terminator sequences the
+// encoder writes are recognised; an OSC is always unfinished, as it ends
+// with another ESC.
+func escapeDone(seq []byte) bool {
+	if len(seq) < 2 {
+		return false
+	}
+	switch seq[1] {
+	case '[':
+		for _, c := range seq[2:] {
+			if c >= 0x40 && c <= 0x7e {
+				return true
+			}
+		}
+		return false
+	case '\\':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package prettyconsole
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// node refers back to itself through parent, as marshalers for trees
+// often do by mistake.
+type node struct {
+	name   string
+	parent *node
+}
+
+func (n *node) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", n.name)
+	return enc.AddObject("parent", n.parent)
+}
+
+func (n *node) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	enc.AppendString(n.name)
+	return enc.AppendArray(n)
+}
+
+func encodeWith(t *testing.T, opts []Option, fields ...zapcore.Field) string {
+	t.Helper()
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	cfg.LevelKey = zapcore.OmitKey
+	buf, err := NewEncoder(cfg, append(opts, WithColour(false))...).EncodeEntry(zapcore.Entry{Message: "msg"}, fields)
+	require.NoError(t, err)
+	defer buf.Free()
+	return buf.String()
+}
+
+func TestMaxDepth(t *testing.T) {
+	n := &node{name: "loop"}
+	n.parent = n
+
+	out := encodeWith(t, []Option{WithMaxDepth(2)},
+		zap.Object("obj", n),
+		zap.Array("arr", n),
+		zap.Array("elems", testArray{testStableMap{"n": n}}),
+	)
+	assert.Equal(t, "> msg\n"+
+		"  ↳ arr=[loop, \n"+
+		"         [loop, \n"+
+		"          <max depth>\n"+
+		"         ]\n"+
+		"        ]\n"+
+		"  ↳ elems=[{n=<max depth>}]\n"+
+		"  ↳ obj.name=loop\n"+
+		"       .parent.name=loop\n"+
+		"              .parent=<max depth>\n", out)
+
+	// The default bound matches the reflection dumper's.
+	out = encodeWith(t, nil, zap.Object("obj", n))
+	assert.Equal(t, maxDumpDepth, strings.Count(out, "name=loop"))
+	assert.True(t, strings.HasSuffix(out, ".parent=<max depth>\n"), out)
+
+	// Invalid depths are ignored.
+	assert.Equal(t, maxDumpDepth, newOptions([]Option{WithMaxDepth(0)}).maxDepth)
+}
+
+func TestMaxEntrySize(t *testing.T) {
+	long := testArray{"aaaaaaaaaa", "bbbbbbbbbb", "cccccccccc", "dddddddddd"}
+
+	out := encodeWith(t, []Option{WithMaxEntrySize(30)}, zap.String("s", "kept"), zap.Array("arr", long))
+	assert.Equal(t, "> msg s=kept\n  ↳ arr=[aaaaaa <truncated 41 bytes>\n", out)
+
+	// A field that starts past the limit keeps only its key.
+	out = encodeWith(t, []Option{WithMaxEntrySize(30)},
+		zap.String("s", strings.Repeat("x", 40)),
+		zap.Object("obj", testStableMap{"a": 1}),
+		zap.Array("elems", testArray{long}),
+	)
+	assert.Equal(t, "> msg s="+strings.Repeat("x", 40)+"\n"+
+		"  ↳ elems=<truncated 51 bytes>\n"+
+		"  ↳ obj=<truncated 4 bytes>\n", out)
+
+	// Unlimited by default, and under the limit nothing changes.
+	assert.Equal(t,
+		encodeWith(t, nil, zap.Array("arr", long)),
+		encodeWith(t, []Option{WithMaxEntrySize(1000)}, zap.Array("arr", long)))
+	assert.Equal(t, 0, newOptions([]Option{WithMaxEntrySize(-1)}).maxEntrySize)
+}
+
+func TestMaxEntrySizeColour(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	enc := NewEncoder(cfg, WithMaxEntrySize(90), WithColourDepth(Colours16))
+	buf, err := enc.EncodeEntry(zapcore.Entry{Message: "msg"},
+		[]zapcore.Field{zap.Array("arr", testArray{"ééééééééééééééééééééééééé"})})
+	require.NoError(t, err)
+	defer buf.Free()
+	out := buf.String()
+
+	// The marker resets any colour the cut left open, and the output is
+	// still valid UTF-8 with every escape sequence whole.
+	assert.Contains(t, out, "\x1b[0m <truncated ")
+	assert.True(t, strings.ToValidUTF8(out, "?") == out, out)
+	assert.NotContains(t, stripANSI(out), "\x1b")
+}
+
+func TestSafeCut(t *testing.T) {
+	b := []byte("ab\x1b[31mé\x1b[0m")
+	for n, want := range map[int]int{
+		1:  1, // plain text
+		3:  2, // inside a CSI sequence
+		6:  2, // just before the CSI's final byte
+		7:  7, // after the CSI
+		8:  7, // inside é
+		10: 9, // inside the reset
+	} {
+		assert.Equal(t, want, safeCut(b, n, 0), "cut at %d", n)
+	}
+	assert.Equal(t, 8, safeCut(b, 8, 8), "never before floor")
+
+	link := []byte(osc8Open + "file:///a" + osc8Close + "text")
+	assert.Equal(t, 0, safeCut(link, 5, 0), "inside an OSC")
+	assert.Equal(t, len(link)-2, safeCut(link, len(link)-2, 0), "inside the link text")
+}
@@ -51,7 +51,15 @@ func (e *prettyConsoleEncoder) AddComplex128(k string, v complex128) {
 func (e *prettyConsoleEncoder) OpenNamespace(key string) {
 	if e.namespaceIndent == 0 {
 		e.buf.AppendString(e.cfg.LineEnding)
-		e.colorizeAtLevel("  ↳ " + key)
+		idx := colourIdx(e.level)
+		if arrow := e.opts.pal.arrow[idx]; arrow == e.opts.pal.key[idx] {
+			e.colorizeKey("  ↳ " + key)
+		} else {
+			e.paint(arrow, "  ↳ ")
+			if len(key) > 0 {
+				e.colorizeKey(key)
+			}
+		}
 		e.namespaceIndent = 4 + len(key)
 	} else {
 		if e.inList {
@@ -59,7 +67,7 @@ func (e *prettyConsoleEncoder) OpenNamespace(key string) {
 			appendSpaces(e.buf, e.namespaceIndent)
 		}
 		if len(key) > 0 {
-			e.colorizeAtLevel(e.keyPrefix + key)
+			e.colorizeKey(e.keyPrefix + key)
 		}
 		e.namespaceIndent += 1 + len(key)
 	}
@@ -69,15 +77,20 @@ func (e *prettyConsoleEncoder) OpenNamespace(key string) {
 }
 
 func (e *prettyConsoleEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
-	enc := e.clone()
+	enc := e.nest()
 	enc.OpenNamespace(key)
+	l := enc.buf.Len()
 
-	if err := marshaler.MarshalLogObject(enc); err != nil {
-		return err
+	if enc.tooDeep() {
+		enc.addMarker("=", "<max depth>")
+	} else if p, err := marshalObject(marshaler, enc); p != "" {
+		enc.replaceWithPanic(l, "=", p)
+	} else if err != nil {
+		enc.addMarshalError(key, err)
 	}
+	enc.truncate(l, "=")
 
-	_, _ = e.buf.Write(enc.buf.Bytes())
-	putPrettyConsoleEncoder(enc)
+	e.unnest(enc)
 
 	e.inList = true
 	e.setIndentSep()
@@ -85,41 +98,81 @@ func (e *prettyConsoleEncoder) AddObject(key string, marshaler zapcore.ObjectMar
 }
 
 func (e *prettyConsoleEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
-	enc := e.clone()
+	enc := e.nest()
 	enc.OpenNamespace(key)
-
-	enc.colorizeAtLevel("=[")
-	enc.namespaceIndent += 2
-	l := enc.buf.Len()
-
-	if err := marshaler.MarshalLogArray(enc); err != nil {
-		return err
-	}
-	if bytes.ContainsRune(enc.buf.Bytes()[l:], '\n') {
-		enc.buf.AppendString(e.cfg.LineEnding)
-		appendSpaces(enc.buf, enc.namespaceIndent-1)
+	start := enc.buf.Len()
+	if enc.tooDeep() {
+		enc.addMarker("=", "<max depth>")
+	} else {
+		enc.colorizeKey("=[")
+		enc.namespaceIndent += 2
+		l := enc.buf.Len()
+
+		p, err := marshalArray(marshaler, enc)
+		if p != "" {
+			enc.replaceWithPanic(start, "=", p)
+		} else {
+			if bytes.ContainsRune(enc.buf.Bytes()[l:], '\n') {
+				enc.buf.AppendString(e.cfg.LineEnding)
+				appendSpaces(enc.buf, enc.namespaceIndent-1)
+			}
+			enc.colorizeAtLevel("]")
+			if err != nil {
+				enc.addMarshalError(key, err)
+			}
+		}
 	}
-	enc.colorizeAtLevel("]")
+	enc.truncate(start, "=")
 
-	_, _ = e.buf.Write(enc.buf.Bytes())
-	putPrettyConsoleEncoder(enc)
+	e.unnest(enc)
 
 	e.inList = true
 	e.setIndentSep()
 	return nil
 }
 
+// replaceWithPanic discards everything written since l, where a callback
+// that panicked began its output, and writes prefix and the panic instead.
+func (e *prettyConsoleEncoder) replaceWithPanic(l int, prefix, panicked string) {
+	e.cutTo(l)
+	e.addMarker(prefix, panicked)
+}
+
+// addMarshalError annotates a partially rendered object or array with the
+// error its marshaler returned, as a ${key}Error field in the error
+// colour - the field zap's own encoders add. Callers then report success,
+// so zap does not add the field a second time.
+func (e *prettyConsoleEncoder) addMarshalError(key string, err error) {
+	// Always separate the annotation, with a space unless the partial
+	// output ended in a multi-line value.
+	e.inList = true
+	if e.listSepIndent < 0 {
+		e.setListSep(e._listSepSpace)
+	}
+	e.addSeparator()
+	lvl := e.level
+	e.level = zapcore.ErrorLevel
+	e.colorizeKey(key + "Error=")
+	e.addSafeString(err.Error())
+	e.level = lvl
+
+	e.inList = true
+	e.setListSep(e._listSepSpace)
+}
+
 func (e *prettyConsoleEncoder) AddReflected(key string, value interface{}) error {
 	enc := e.clone()
 	enc.OpenNamespace(key)
 
-	enc.colorizeAtLevel("=")
+	enc.colorizeKey("=")
 	enc.namespaceIndent += 1
 	l := enc.buf.Len()
 	iw := indentingWriter{
 		buf:        enc.buf,
 		indent:     enc.namespaceIndent,
 		lineEnding: []byte(e.cfg.LineEnding),
+		maxDepth:   e.opts.maxDepth,
+		values:     &e.opts.values,
 	}
 
 	switch v := value.(type) {
@@ -129,7 +182,11 @@ func (e *prettyConsoleEncoder) AddReflected(key string, value interface{}) error
 		}
 	default:
 		if e.cfg.NewReflectedEncoder != nil {
-			if err := e.cfg.NewReflectedEncoder(iw).Encode(value); err != nil {
+			p, err := encodeReflected(e.cfg.NewReflectedEncoder, iw, value)
+			if p != "" {
+				enc.replaceWithPanic(l, "", p)
+			} else if err != nil {
+				putPrettyConsoleEncoder(enc)
 				return err
 			}
 		}
@@ -153,7 +210,7 @@ func (e *prettyConsoleEncoder) AddReflected(key string, value interface{}) error
 func (e *prettyConsoleEncoder) AddByteString(key string, value []byte) {
 	e.addSeparator()
 	e.addKey(key)
-	e.appendSafeByte(value)
+	addStringValue(e, value, e._listSepSpace)
 
 	e.inList = true
 	e.setListSep(e._listSepSpace)
@@ -191,11 +248,12 @@ func (e *prettyConsoleEncoder) addComplex(key string, c complex128, precision in
 func (e *prettyConsoleEncoder) AddDuration(key string, value time.Duration) {
 	e.addSeparator()
 	e.addKey(key)
+	value = e.opts.values.duration(value)
 	cur := e.buf.Len()
 	// Both of these append, and we're at the first element of the sublist
 	e.inList = false
 	if e.cfg.EncodeDuration != nil {
-		e.cfg.EncodeDuration(value, e)
+		e.safeCall(func() { e.cfg.EncodeDuration(value, e) })
 	}
 	if cur == e.buf.Len() {
 		// User-supplied EncodeDuration is absent or a no-op. Fall back to
@@ -228,7 +286,7 @@ func (e *prettyConsoleEncoder) AddInt64(key string, value int64) {
 func (e *prettyConsoleEncoder) AddString(key, value string) {
 	e.addSeparator()
 	e.addKey(key)
-	e.addSafeString(value)
+	addStringValue(e, value, e._listSepSpace)
 
 	e.inList = true
 	e.setListSep(e._listSepSpace)
@@ -311,7 +369,7 @@ func (e *prettyConsoleEncoder) AddTime(key string, value time.Time) {
 	e.addKey(key)
 	// Don't use configured time encoder as it's been customized to display the
 	// log's time, .e.g, this will be coloured dark grey in time.Kitchen
-	e.buf.AppendTime(value, time.RFC3339)
+	e.appendTimeValue(value)
 
 	e.inList = true
 	e.setListSep(e._listSepSpace)
@@ -203,10 +203,40 @@ type failingMarshaler struct{}
 
 func (failingMarshaler) MarshalLogObject(zapcore.ObjectEncoder) error { return errors.New("boom") }
 
+// partialMarshaler fails after writing some of its fields.
+type partialMarshaler struct{}
+
+func (partialMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", "Big Bird")
+	enc.AddInt("age", 18)
+	return errors.New("boom\nagain")
+}
+
+// partialArrayMarshaler mirrors partialMarshaler for arrays.
+type partialArrayMarshaler struct{}
+
+func (partialArrayMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	enc.AppendInt(1)
+	enc.AppendInt(2)
+	return errors.New("boom")
+}
+
 func TestObjectMarshalerError(t *testing.T) {
-	out := encodePlain(t, zap.Object("obj", failingMarshaler{}))
-	// zapcore surfaces marshal errors as an extra <key>Error field.
-	assert.Contains(t, out, "objError=boom")
+	// Like zapcore, marshal errors are surfaced as a <key>Error field, once.
+	assert.Equal(t, "> msg\n  ↳ obj objError=boom\n", encodePlain(t, zap.Object("obj", failingMarshaler{})))
+
+	// Whatever the marshaler wrote before failing is kept.
+	assert.Equal(t, "> msg z=after\n  ↳ obj.name=Big Bird .age=18 objError=boom\\nagain\n",
+		encodePlain(t, zap.Object("obj", partialMarshaler{}), zap.String("z", "after")))
+	assert.Equal(t, "> msg\n  ↳ o.a=1\n     .in.name=Big Bird .age=18 inError=boom\\nagain\n",
+		encodePlain(t, zap.Object("o", testStableMap{"in": partialMarshaler{}, "a": 1})))
+
+	// The annotation is in the error colour, whatever the entry's level.
+	buf, err := NewEncoder(NewEncoderConfig()).EncodeEntry(zapcore.Entry{Level: zapcore.DebugLevel},
+		[]zapcore.Field{zap.Object("obj", failingMarshaler{})})
+	require.NoError(t, err)
+	defer buf.Free()
+	assert.Contains(t, tagANSI(buf.String()), "<cyan>  ↳ obj<r><cyan> <r><red>objError=<r>boom\n")
 }
 
 // directMarshaler calls the ObjectEncoder methods that zap's field types
@@ -232,9 +262,8 @@ type failingArrayMarshaler struct{}
 func (failingArrayMarshaler) MarshalLogArray(zapcore.ArrayEncoder) error { return errors.New("boom") }
 
 func TestArrayMarshalerError(t *testing.T) {
-	out := encodePlain(t, zap.Array("arr", failingArrayMarshaler{}))
-	// zapcore surfaces marshal errors as an extra <key>Error field.
-	assert.Contains(t, out, "arrError=boom")
+	assert.Equal(t, "> msg\n  ↳ arr=[] arrError=boom\n", encodePlain(t, zap.Array("arr", failingArrayMarshaler{})))
+	assert.Equal(t, "> msg\n  ↳ arr=[1, 2] arrError=boom\n", encodePlain(t, zap.Array("arr", partialArrayMarshaler{})))
 }
 
 type erroringReflected struct{}
@@ -0,0 +1,230 @@
+package prettyconsole
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Option configures an encoder built by NewEncoder.
+type Option func(*options)
+
+// options holds everything an Option can set. It is resolved once, when the
+// encoder is built, and shared read-only by every clone after that.
+type options struct {
+	theme  Theme
+	colour bool
+	depth  ColourDepth
+	// hiddenFrames are path.Match patterns, or package trees ending in
+	// /..., of stacktrace functions to hide.
+	hiddenFrames []string
+	// hyperlinks is the WithHyperlinks URL template, cleared when colour
+	// is off.
+	hyperlinks string
+	// sourceSnippets enables source snippets for error-level entries.
+	sourceSnippets bool
+	// maxDepth and maxEntrySize bound nested marshaler output; a
+	// maxEntrySize of 0 means no limit.
+	maxDepth     int
+	maxEntrySize int
+	levelStyle   LevelStyle
+	levelWidth   int
+	quoting      QuotePolicy
+	values       valueFormat
+
+	pal palette
+}
+
+// defaultOptions is shared by every encoder built without options.
+var defaultOptions = cachedOptions{build: func() *options { return newOptions(nil) }}
+
+// cachedOptions resolves options once, and again after RegisterLevel,
+// since level labels are compiled into the palette.
+type cachedOptions struct {
+	build    func() *options
+	resolved atomic.Pointer[resolvedOptions]
+}
+
+type resolvedOptions struct {
+	gen uint64
+	o   *options
+}
+
+func (c *cachedOptions) get() *options {
+	gen := levels.gen.Load()
+	if r := c.resolved.Load(); r != nil && r.gen == gen {
+		return r.o
+	}
+	o := c.build()
+	c.resolved.Store(&resolvedOptions{gen: gen, o: o})
+	return o
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{theme: DefaultTheme(), colour: true, depth: DetectColourDepth(), maxDepth: maxDumpDepth, values: defaultValueFormat}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if !o.colour {
+		o.hyperlinks = ""
+	}
+	o.pal = newPalette(o)
+	return o
+}
+
+// WithTheme sets the colours the encoder renders with. See DefaultTheme and
+// LightTheme for starting points.
+func WithTheme(t Theme) Option {
+	return func(o *options) {
+		o.theme = t
+	}
+}
+
+// WithColour turns ANSI colour codes on or off. Without colour the output
+// keeps exactly the same layout, which suits files and CI logs. Colour is
+// on by default; see ColourEnabled to decide based on the output.
+func WithColour(enabled bool) Option {
+	return func(o *options) {
+		o.colour = enabled
+	}
+}
+
+// WithColourDepth sets how many colours the terminal supports. Colours
+// beyond that depth are downsampled to the closest supported colour. The
+// default is detected from the environment by DetectColourDepth.
+func WithColourDepth(d ColourDepth) Option {
+	return func(o *options) {
+		o.depth = d
+	}
+}
+
+// WithHiddenFrames hides stacktrace frames whose function name matches any
+// of the patterns, such as "testing.*" or "net/http.*". Patterns use
+// path.Match syntax against the full function name, so * does not match
+// across a /: "github.com/foo/*" hides github.com/foo/bar.Fn but not
+// github.com/foo/bar/baz.Fn. As with go list, a pattern ending in /...
+// hides a package and every package below it: "github.com/foo/..." hides
+// both, and github.com/foo.Fn too. Invalid patterns are ignored. Each run
+// of hidden frames is summarised on a single line.
+func WithHiddenFrames(patterns ...string) Option {
+	return func(o *options) {
+		o.hiddenFrames = append(o.hiddenFrames, patterns...)
+	}
+}
+
+// WithHyperlinks wraps callers and stacktrace locations in OSC 8 terminal
+// hyperlinks, so clicking one opens the file. template is the link URL, in
+// which {abs} is replaced by the absolute file path (relative paths are
+// resolved against the working directory, and the path is query-escaped
+// after a ?) and {line} by the line number; HyperlinkFile, HyperlinkVSCode
+// and HyperlinkIDEA cover common editors. In binaries built with -trimpath
+// only the main module's files are linked, as the rest cannot be found.
+// Hyperlinks are escape sequences, so they are only written with colour on.
+func WithHyperlinks(template string) Option {
+	return func(o *options) {
+		o.hyperlinks = template
+	}
+}
+
+// WithSourceSnippets prints the source line an Error level (or above) entry
+// was logged from, and the line each pkg/errors error in it was created
+// at, with two lines of context either side. The most recently shown
+// source files are cached; files that cannot be read are skipped silently.
+func WithSourceSnippets(enabled bool) Option {
+	return func(o *options) {
+		o.sourceSnippets = enabled
+	}
+}
+
+// WithMaxDepth bounds how deeply ObjectMarshalers, ArrayMarshalers and
+// values printed by the reflection dumper may nest, so a marshaler that
+// refers back to itself cannot overflow the stack. Anything nested deeper
+// renders as <max depth>. The default is 64; values below 1 are ignored.
+func WithMaxDepth(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxDepth = n
+		}
+	}
+}
+
+// WithMaxEntrySize caps entries at roughly n bytes by cutting any
+// ObjectMarshaler or ArrayMarshaler output that would take the entry past
+// n, ending it with a <truncated N bytes> marker. Other fields are never
+// cut, so an entry can still exceed n. The default of 0 means no limit.
+func WithMaxEntrySize(n int) Option {
+	return func(o *options) {
+		o.maxEntrySize = max(n, 0)
+	}
+}
+
+// WithLevelStyle sets how level labels are written. The default is
+// LevelShort; unknown styles are ignored.
+func WithLevelStyle(s LevelStyle) Option {
+	return func(o *options) {
+		if s >= LevelShort && s <= LevelEmoji {
+			o.levelStyle = s
+		}
+	}
+}
+
+// WithLevelWidth pads level labels with spaces to n columns, so messages
+// line up whatever the level. Emoji and East Asian wide characters count
+// as two columns, including symbols such as ⚠ when followed by the U+FE0F
+// emoji variation selector; terminals vary, so pick labels that they agree
+// on. The default of 0 never pads.
+func WithLevelWidth(n int) Option {
+	return func(o *options) {
+		o.levelWidth = max(n, 0)
+	}
+}
+
+// WithQuoting sets when string field values, array elements and messages
+// are written in quotes. The default is QuoteNever; unknown policies are
+// ignored.
+func WithQuoting(p QuotePolicy) Option {
+	return func(o *options) {
+		if p >= QuoteNever && p <= QuoteAlways {
+			o.quoting = p
+		}
+	}
+}
+
+// WithValueTimeLayout sets the layout time fields are written in, whether
+// added with zap.Time, zap.Times or found inside a value printed by the
+// reflection dumper. The entry's own timestamp is unaffected; it is set by
+// the EncoderConfig's EncodeTime. The default is time.RFC3339, which drops
+// sub-second precision; use a layout such as
+// "2006-01-02T15:04:05.000000000Z07:00" for fixed nanoseconds.
+func WithValueTimeLayout(layout string) Option {
+	return func(o *options) {
+		o.values.timeLayout = layout
+	}
+}
+
+// WithValueTimeLocation converts time fields to loc, such as time.UTC or
+// time.Local, before writing them. By default each time is written in its
+// own location.
+func WithValueTimeLocation(loc *time.Location) Option {
+	return func(o *options) {
+		o.values.location = loc
+	}
+}
+
+// WithRelativeValueTimes writes time fields as their age when the entry is
+// encoded, in their largest whole unit, such as 3m ago or in 2h, in place
+// of a layout.
+func WithRelativeValueTimes(enabled bool) Option {
+	return func(o *options) {
+		o.values.relative = enabled
+	}
+}
+
+// WithValueDurationPrecision rounds duration fields, and durations found
+// by the reflection dumper, to a multiple of d before they are written.
+// Duration fields are still written by the EncoderConfig's EncodeDuration.
+// The default of 0 leaves them unrounded.
+func WithValueDurationPrecision(d time.Duration) Option {
+	return func(o *options) {
+		o.values.durationPrecision = max(d, 0)
+	}
+}
@@ -0,0 +1,63 @@
+package prettyconsole
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// User callbacks (marshalers, reflected encoders and the EncoderConfig
+// functions) run inside the logger, so a panic in one would take the
+// process down with the log line. Each call goes through one of the
+// helpers below, and a panic is rendered in place of the value.
+
+// panicValue renders a recovered panic. As in encodeError, a nil pointer
+// receiver is the likeliest cause, and "<nil>" is the nicer result.
+func panicValue(receiver, r interface{}) string {
+	if v := reflect.ValueOf(receiver); v.Kind() == reflect.Pointer && v.IsNil() {
+		return "<nil>"
+	}
+	return fmt.Sprintf("<PANIC: %v>", r)
+}
+
+func marshalObject(m zapcore.ObjectMarshaler, enc zapcore.ObjectEncoder) (panicked string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = panicValue(m, r)
+		}
+	}()
+	return "", m.MarshalLogObject(enc)
+}
+
+func marshalArray(m zapcore.ArrayMarshaler, enc zapcore.ArrayEncoder) (panicked string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = panicValue(m, r)
+		}
+	}()
+	return "", m.MarshalLogArray(enc)
+}
+
+func encodeReflected(newEncoder func(io.Writer) zapcore.ReflectedEncoder, w io.Writer, v interface{}) (panicked string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = panicValue(v, r)
+		}
+	}()
+	return "", newEncoder(w).Encode(v)
+}
+
+// safeCall runs an EncoderConfig callback that appends to e, appending the
+// panic in place of its output if it panics.
+func (e *prettyConsoleEncoder) safeCall(f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			e.addSeparator()
+			e.addSafeString(panicValue(nil, r))
+			e.inList = true
+		}
+	}()
+	f()
+}
@@ -0,0 +1,98 @@
+package prettyconsole
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type panickingMarshaler struct{ name string }
+
+func (m *panickingMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("partial", "dropped")
+	panic("boom " + m.name)
+}
+
+func (m *panickingMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	enc.AppendString("dropped")
+	panic("boom " + m.name)
+}
+
+type panickingReflected struct{}
+
+func (panickingReflected) Encode(interface{}) error { panic("boom") }
+
+func TestMarshalerPanics(t *testing.T) {
+	m := &panickingMarshaler{name: "x"}
+	var nilM *panickingMarshaler
+	out := encodePlain(t,
+		zap.String("a", "still here"),
+		zap.Object("obj", m),
+		zap.Object("nil_obj", nilM),
+		zap.Array("arr", m),
+		zap.Array("elems", testArray{1, m, testArray{2, m}, nilM, "after"}),
+		zap.Object("nested", testStableMap{"inner": m, "ok": 1}),
+	)
+	assert.Equal(t, "> msg a=still here\n"+
+		"  ↳ arr=<PANIC: boom x>\n"+
+		"  ↳ elems=[1, \n"+
+		"           <PANIC: boom x>, \n"+
+		"           [2, \n"+
+		"            <PANIC: boom x>\n"+
+		"           ], \n"+
+		"           <nil>, after\n"+
+		"          ]\n"+
+		"  ↳ nested.inner=<PANIC: boom x>\n"+
+		"          .ok=1\n"+
+		"  ↳ nil_obj=<nil>\n"+
+		"  ↳ obj=<PANIC: boom x>\n", out)
+}
+
+func TestReflectedEncoderPanics(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	cfg.NewReflectedEncoder = func(io.Writer) zapcore.ReflectedEncoder { return panickingReflected{} }
+	buf, err := NewEncoder(cfg, WithColour(false)).EncodeEntry(zapcore.Entry{Message: "m"}, []zapcore.Field{
+		zap.Reflect("r", struct{}{}),
+		zap.Array("ra", testArray{struct{}{}, "after"}),
+	})
+	require.NoError(t, err)
+	defer buf.Free()
+	assert.Equal(t, "INF > m\n  ↳ ra=[<PANIC: boom>, after]\n  ↳ r=<PANIC: boom>\n", buf.String())
+}
+
+func TestEncoderConfigCallbackPanics(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.CallerKey = "C"
+	cfg.EncodeTime = func(time.Time, zapcore.PrimitiveArrayEncoder) { panic("time") }
+	cfg.EncodeLevel = func(zapcore.Level, zapcore.PrimitiveArrayEncoder) { panic("level") }
+	cfg.EncodeName = func(string, zapcore.PrimitiveArrayEncoder) { panic("name") }
+	cfg.EncodeCaller = func(zapcore.EntryCaller, zapcore.PrimitiveArrayEncoder) { panic("caller") }
+	cfg.EncodeDuration = func(time.Duration, zapcore.PrimitiveArrayEncoder) { panic("duration") }
+
+	buf, err := NewEncoder(cfg, WithColour(false)).EncodeEntry(zapcore.Entry{
+		Message:    "m",
+		LoggerName: "svc",
+		Caller:     zapcore.NewEntryCaller(0, "/src/main.go", 1, true),
+	}, []zapcore.Field{
+		zap.Duration("d", time.Second),
+		zap.Durations("ds", []time.Duration{time.Second}),
+	})
+	require.NoError(t, err)
+	defer buf.Free()
+	assert.Equal(t, "<PANIC: time> <PANIC: level> <PANIC: name> <PANIC: caller> > m d=<PANIC: duration>\n"+
+		"  ↳ ds=[<PANIC: duration>]\n", buf.String())
+}
+
+func TestPanicValue(t *testing.T) {
+	var nilM *panickingMarshaler
+	assert.Equal(t, "<nil>", panicValue(nilM, "x"))
+	assert.Equal(t, "<PANIC: x>", panicValue(&panickingMarshaler{}, "x"))
+	assert.Equal(t, "<PANIC: x>", panicValue(nil, "x"))
+	assert.Equal(t, "<PANIC: 3>", panicValue(testArray{}, 3))
+}
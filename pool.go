@@ -27,6 +27,7 @@ func getPrettyConsoleEncoder() *prettyConsoleEncoder {
 
 func putPrettyConsoleEncoder(e *prettyConsoleEncoder) {
 	e.cfg = nil
+	e.opts = nil
 	if e.buf != nil {
 		putBuffer(e.buf)
 	}
@@ -36,6 +37,9 @@ func putPrettyConsoleEncoder(e *prettyConsoleEncoder) {
 	e.inList = false
 	e.listSep = ""
 	e.listSepIndent = -1
+	e.nesting = 0
+	e.offset = 0
+	e.hidden = 0
 	e._listSepSpace = ""
 	e._listSepComma = ""
 
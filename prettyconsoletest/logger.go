@@ -0,0 +1,85 @@
+package prettyconsoletest
+
+import (
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+
+	prettyconsole "github.com/thessem/zap-prettyconsole"
+)
+
+// LoggerOption configures a logger built by NewLogger.
+type LoggerOption func(*loggerOptions)
+
+type loggerOptions struct {
+	level   zapcore.LevelEnabler
+	failAt  zapcore.Level
+	fail    bool
+	encOpts []prettyconsole.Option
+	zapOpts []zap.Option
+}
+
+// WithLevel sets the level the logger logs at. The default is DebugLevel.
+func WithLevel(enab zapcore.LevelEnabler) LoggerOption {
+	return func(o *loggerOptions) {
+		o.level = enab
+	}
+}
+
+// WithFailAt makes the test fail when an entry at lvl or above is logged,
+// for example zapcore.DPanicLevel to catch the mistakes DPanic is for.
+func WithFailAt(lvl zapcore.Level) LoggerOption {
+	return func(o *loggerOptions) {
+		o.failAt, o.fail = lvl, true
+	}
+}
+
+// WithEncoderOptions passes options to the logger's encoder.
+func WithEncoderOptions(opts ...prettyconsole.Option) LoggerOption {
+	return func(o *loggerOptions) {
+		o.encOpts = append(o.encOpts, opts...)
+	}
+}
+
+// WithWrapOptions passes options to the logger's zap.Logger.
+func WithWrapOptions(opts ...zap.Option) LoggerOption {
+	return func(o *loggerOptions) {
+		o.zapOpts = append(o.zapOpts, opts...)
+	}
+}
+
+// NewLogger returns a logger that writes each entry through t.Log, like
+// zaptest.NewLogger but pretty: output is grouped under the test that
+// logged it, and only shown if it fails or with -v. Colour is only used
+// with -v on a terminal, where output is not captured into logs. Fatal
+// entries end the test rather than the test binary, and errors writing
+// entries fail the test.
+func NewLogger(t testing.TB, opts ...LoggerOption) *zap.Logger {
+	o := loggerOptions{level: zapcore.DebugLevel}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	colour := testing.Verbose() && prettyconsole.ColourEnabled(os.Stdout)
+	encOpts := append([]prettyconsole.Option{prettyconsole.WithColour(colour)}, o.encOpts...)
+	w := zaptest.NewTestingWriter(t)
+	zapOpts := []zap.Option{
+		zap.ErrorOutput(w.WithMarkFailed(true)),
+		zap.WithFatalHook(zapcore.WriteThenGoexit),
+	}
+	if o.fail {
+		zapOpts = append(zapOpts, zap.Hooks(func(e zapcore.Entry) error {
+			if e.Level >= o.failAt {
+				t.Errorf("%s logged: %s", e.Level.CapitalString(), e.Message)
+			}
+			return nil
+		}))
+	}
+	zapOpts = append(zapOpts, o.zapOpts...)
+
+	enc := prettyconsole.NewEncoder(prettyconsole.NewEncoderConfig(), encOpts...)
+	return zap.New(zapcore.NewCore(enc, w, o.level), zapOpts...)
+}
@@ -0,0 +1,106 @@
+package prettyconsoletest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	prettyconsole "github.com/thessem/zap-prettyconsole"
+)
+
+// loggerTB records what a logger does to its test.
+type loggerTB struct {
+	testing.TB
+	mu     sync.Mutex
+	logs   []string
+	errors []string
+	failed bool
+}
+
+func (f *loggerTB) Logf(format string, args ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func (f *loggerTB) Errorf(format string, args ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+	f.failed = true
+}
+
+func (f *loggerTB) Fail() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed = true
+}
+
+func TestNewLogger(t *testing.T) {
+	tb := &loggerTB{TB: t}
+	// Encoder options apply after colour detection, so they can override it.
+	logger := NewLogger(tb, WithEncoderOptions(prettyconsole.WithColour(false)))
+	logger.Debug("debug")
+	logger.Info("m", zap.Object("o", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddInt("a", 1)
+		return nil
+	})))
+
+	if assert.Len(t, tb.logs, 2) {
+		assert.Regexp(t, `^\d+:\d\d[AP]M DBG > debug$`, tb.logs[0])
+		assert.Regexp(t, "^\\d+:\\d\\d[AP]M INF > m\n  ↳ o.a=1$", tb.logs[1], "the trailing newline is left to t.Log")
+	}
+	assert.False(t, tb.failed)
+	assert.Empty(t, tb.errors)
+}
+
+func TestNewLoggerOptions(t *testing.T) {
+	tb := &loggerTB{TB: t}
+	var hooked []string
+	logger := NewLogger(tb,
+		WithLevel(zapcore.WarnLevel),
+		WithFailAt(zapcore.DPanicLevel),
+		WithEncoderOptions(prettyconsole.WithColour(false), prettyconsole.WithLevelStyle(prettyconsole.LevelFull)),
+		WithWrapOptions(zap.Hooks(func(e zapcore.Entry) error {
+			hooked = append(hooked, e.Message)
+			return nil
+		})),
+	)
+	logger.Info("dropped")
+	logger.Error("error")
+	assert.False(t, tb.failed, "errors below the fail level pass")
+	logger.DPanic("dpanic")
+
+	assert.Len(t, tb.logs, 2)
+	assert.Contains(t, tb.logs[1], "DPANIC > dpanic")
+	assert.True(t, tb.failed)
+	assert.Equal(t, []string{"DPANIC logged: dpanic"}, tb.errors)
+	assert.Equal(t, []string{"error", "dpanic"}, hooked)
+}
+
+func TestNewLoggerFatal(t *testing.T) {
+	tb := &loggerTB{TB: t}
+	logger := NewLogger(tb, WithEncoderOptions(prettyconsole.WithColour(false)))
+
+	// Fatal ends the goroutine, not the test binary.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Fatal("fatal")
+		t.Error("Fatal returned")
+	}()
+	<-done
+	assert.Contains(t, tb.logs[0], "FTL > fatal")
+}
+
+func TestNewLoggerColour(t *testing.T) {
+	tb := &loggerTB{TB: t}
+	t.Setenv("FORCE_COLOR", "1")
+	NewLogger(tb).Info("m")
+	// Colour needs -v as well as a terminal.
+	assert.Equal(t, testing.Verbose(), len(StripANSI(tb.logs[0])) != len(tb.logs[0]))
+}
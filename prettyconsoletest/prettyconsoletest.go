@@ -0,0 +1,64 @@
+// Package prettyconsoletest helps test code that logs through the
+// prettyconsole encoder: NewLogger is a pretty zaptest.NewLogger, and the
+// remaining helpers snapshot-test output, such as how your own
+// MarshalLogObject implementations render.
+//
+// Raw output is full of ANSI escape sequences, which make expected values
+// impossible to read or diff. TagANSI replaces each escape sequence with a
+// readable tag, and UntagANSI turns the tags back. Tags are not escaped, so
+// a tagged comparison cannot tell a colour from logged text that reads
+// like its tag; compare raw bytes where that matters. NormalizeLocations
+// replaces file paths and line numbers, which change with every edit, and
+// AssertGolden compares the result against a golden file.
+package prettyconsoletest
+
+import (
+	"testing"
+
+	"github.com/thessem/zap-prettyconsole/internal/snapshot"
+)
+
+// UpdateEnv is the environment variable that, set to a true value such as
+// 1, makes AssertGolden rewrite golden files instead of comparing against
+// them.
+const UpdateEnv = snapshot.UpdateEnv
+
+// TagANSI replaces the ANSI escape sequences in s with readable tags: the
+// colours of the default theme by name, such as <red>, bold as <bold> and
+// reset as <r>. Any other colour sequence keeps its code, as in
+// <esc:38;5;208>. Tags are not escaped, so text in s that already reads
+// like a tag, such as a logged "<red>", is indistinguishable from the
+// sequence. OSC 8 hyperlinks, from WithHyperlinks, are left as they are.
+func TagANSI(s string) string {
+	return snapshot.TagANSI(s)
+}
+
+// UntagANSI turns the tags TagANSI writes back into escape sequences. Text
+// that only looked like a tag before tagging is turned into one too.
+func UntagANSI(s string) string {
+	return snapshot.UntagANSI(s)
+}
+
+// StripANSI removes ANSI colour sequences and OSC 8 hyperlinks, keeping
+// the linked text, for tests that only care about the text content.
+func StripANSI(s string) string {
+	return snapshot.StripANSI(s)
+}
+
+// NormalizeLocations replaces file:line locations, such as those in
+// callers and stacktraces, with "/<some_file>:<line_number>", so golden
+// files survive unrelated edits and different checkouts. Remember to also
+// check by hand with -trimpath.
+func NormalizeLocations(s string) string {
+	return snapshot.NormalizeLocations(s)
+}
+
+// AssertGolden compares got against testdata/<test name>.golden, with
+// subtest separators replaced by underscores. Running the tests with
+// PRETTYCONSOLE_UPDATE_GOLDEN=1, or with -update if the test package
+// defines that flag, writes got to the file instead, so an intentional
+// output change becomes a reviewable diff rather than a hand-edit.
+func AssertGolden(t testing.TB, got string) {
+	t.Helper()
+	snapshot.AssertGolden(t, got)
+}
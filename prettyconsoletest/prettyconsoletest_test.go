@@ -0,0 +1,94 @@
+package prettyconsoletest
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	prettyconsole "github.com/thessem/zap-prettyconsole"
+)
+
+func TestTagANSI(t *testing.T) {
+	raw := "\x1b[32mINF\x1b[0m plain \x1b[1mx\x1b[0m \x1b[95my\x1b[0m \x1b[38;5;208mz\x1b[0m"
+	tagged := "<green>INF<r> plain <bold>x<r> <esc:95>y<r> <esc:38;5;208>z<r>"
+	assert.Equal(t, tagged, TagANSI(raw))
+	assert.Equal(t, raw, UntagANSI(tagged))
+	assert.Equal(t, "INF plain x y z", StripANSI(raw))
+
+	linked := "\x1b]8;;file:///a.go\x1b\\\x1b[1ma.go:1\x1b[0m\x1b]8;;\x1b\\ \x1b]8;;file:///b.go\x07b.go:2\x1b]8;;\x07"
+	assert.Equal(t, "a.go:1 b.go:2", StripANSI(linked))
+
+	// Tags are not escaped: literal tag text tags the same as the sequence.
+	assert.Equal(t, TagANSI("\x1b[31mx"), TagANSI("<red>x"))
+}
+
+func TestNormalizeLocations(t *testing.T) {
+	assert.Equal(t,
+		"C /<some_file>:<line_number> > m\n"+
+			"  ↳ stacktrace=main.main /<some_file>:<line_number>\n"+
+			"               testing.tRunner /<some_file>:<line_number>",
+		NormalizeLocations("C github.com/acme/pkg/file.go:12 > m\n"+
+			"  ↳ stacktrace=main.main /src/main.go:10\n"+
+			"               testing.tRunner /usr/local/go@1.23/src/testing/testing.go:1792"))
+}
+
+// fakeTB records assertion failures instead of failing the test.
+type fakeTB struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeTB) Helper()                              {}
+func (f *fakeTB) Errorf(string, ...interface{})        { f.failed = true }
+func (f *fakeTB) FailNow()                             { f.failed = true }
+func (f *fakeTB) Name() string                         { return f.TB.Name() }
+func (f *fakeTB) Logf(format string, a ...interface{}) {}
+
+func TestAssertGolden(t *testing.T) {
+	// A MarshalLogObject snapshot, the way downstream tests use this
+	// package.
+	cfg := prettyconsole.NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	buf, err := prettyconsole.NewEncoder(cfg, prettyconsole.WithColourDepth(prettyconsole.Colours16)).EncodeEntry(zapcore.Entry{
+		Level:   zapcore.WarnLevel,
+		Message: "m",
+		Stack:   "main.main\n\t/src/main.go:10",
+	}, []zapcore.Field{zap.Strings("list", []string{"a", "b"})})
+	require.NoError(t, err)
+	defer buf.Free()
+	AssertGolden(t, TagANSI(NormalizeLocations(buf.String())))
+	if b, _ := strconv.ParseBool(os.Getenv(UpdateEnv)); b {
+		return
+	}
+
+	tb := &fakeTB{TB: t}
+	AssertGolden(tb, "different")
+	assert.True(t, tb.failed, "a mismatch fails")
+
+	t.Run("missing", func(t *testing.T) {
+		tb := &fakeTB{TB: t}
+		AssertGolden(tb, "anything")
+		assert.True(t, tb.failed, "a missing golden file fails")
+	})
+}
+
+func TestAssertGoldenUpdate(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+	t.Setenv(UpdateEnv, "1")
+
+	t.Run("sub", func(t *testing.T) {
+		AssertGolden(t, "written")
+	})
+	got, err := os.ReadFile(filepath.Join("testdata", "TestAssertGoldenUpdate_sub.golden"))
+	require.NoError(t, err)
+	assert.Equal(t, "written", string(got))
+}
@@ -0,0 +1,90 @@
+package prettyconsole
+
+import "strings"
+
+// QuotePolicy selects when string values are written in quotes.
+type QuotePolicy int
+
+const (
+	// QuoteNever writes strings bare, as zap's console encoder does.
+	QuoteNever QuotePolicy = iota
+	// QuoteWhenNeeded quotes strings that would be ambiguous bare: empty
+	// ones, ones with leading or trailing spaces, ones containing the
+	// separator that follows them, and ones that look like a number, a
+	// bool or null.
+	QuoteWhenNeeded
+	// QuoteAlways quotes every string.
+	QuoteAlways
+)
+
+// addStringValue writes a string field value, array element or message,
+// quoted as the quoting policy asks. sep is what could follow it and be
+// mistaken for part of it. Quotes are written in the key colour, so they
+// read as structure; quotes inside the string are escaped either way.
+func addStringValue[T string | []byte](e *prettyConsoleEncoder, s T, sep string) {
+	quoted := e.opts.quoting == QuoteAlways ||
+		e.opts.quoting == QuoteWhenNeeded && needsQuotes(s, sep)
+	if quoted {
+		e.colorizeKey(`"`)
+	}
+	switch s := any(s).(type) {
+	case string:
+		e.addSafeString(s)
+	case []byte:
+		e.appendSafeByte(s)
+	}
+	if quoted {
+		e.colorizeKey(`"`)
+	}
+}
+
+// needsQuotes reports whether s would be ambiguous written bare. sep is
+// trimmed of spaces unless it is only spaces, so ", " matches a bare comma.
+func needsQuotes[T string | []byte](s T, sep string) bool {
+	if len(s) == 0 || s[0] == ' ' || s[len(s)-1] == ' ' {
+		return true
+	}
+	if t := strings.TrimSpace(sep); t != "" {
+		sep = t
+	}
+	return sep != "" && contains(s, sep) || looksLikeLiteral(s)
+}
+
+func contains[T string | []byte](s T, sub string) bool {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if string(s[i:i+len(sub)]) == sub {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeLiteral reports whether s reads as a bool, null or a number as
+// this encoder writes them, such as -12, 3.5e-07, NaN or +Inf.
+func looksLikeLiteral[T string | []byte](s T) bool {
+	switch string(s) {
+	case "true", "false", "null", "nil", "NaN", "+Inf", "-Inf":
+		return true
+	}
+	i := 0
+	if s[0] == '-' || s[0] == '+' {
+		i++
+	}
+	digits, dot, exp := false, false, false
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			digits = true
+		case c == '.' && !dot && !exp:
+			dot = true
+		case (c == 'e' || c == 'E') && digits && !exp:
+			exp, digits = true, false
+			if i+1 < len(s) && (s[i+1] == '-' || s[i+1] == '+') {
+				i++
+			}
+		default:
+			return false
+		}
+	}
+	return digits
+}
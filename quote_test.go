@@ -0,0 +1,100 @@
+package prettyconsole
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestQuoting(t *testing.T) {
+	fields := []zapcore.Field{
+		zap.String("empty", ""),
+		zap.String("name", "Big Bird"),
+		zap.String("plain", "bird"),
+		zap.String("num", "-1.5e3"),
+		zap.ByteString("bool", []byte("true")),
+		zap.String("quote", `say "hi"`),
+		zap.Strings("list", []string{"a", "b, c", " d", "12"}),
+	}
+	for _, tt := range []struct {
+		policy QuotePolicy
+		msg    string
+		want   string
+	}{
+		{QuoteNever, "x=1", `x=1 bool=true empty= name=Big Bird num=-1.5e3 plain=bird quote=say \"hi\"` + "\n" +
+			"  ↳ list=[a, b, c,  d, 12]\n"},
+		{QuoteWhenNeeded, "x=1", `"x=1" bool="true" empty="" name="Big Bird" num="-1.5e3" plain=bird quote="say \"hi\""` + "\n" +
+			`  ↳ list=[a, "b, c", " d", "12"]` + "\n"},
+		{QuoteWhenNeeded, "hello world", `hello world bool="true" empty="" name="Big Bird" num="-1.5e3" plain=bird quote="say \"hi\""` + "\n" +
+			`  ↳ list=[a, "b, c", " d", "12"]` + "\n"},
+		{QuoteAlways, "hello world", `"hello world" bool="true" empty="" name="Big Bird" num="-1.5e3" plain="bird" quote="say \"hi\""` + "\n" +
+			`  ↳ list=["a", "b, c", " d", "12"]` + "\n"},
+	} {
+		cfg := NewEncoderConfig()
+		cfg.TimeKey = zapcore.OmitKey
+		cfg.LevelKey = zapcore.OmitKey
+		buf, err := NewEncoder(cfg, WithColour(false), WithQuoting(tt.policy)).
+			EncodeEntry(zapcore.Entry{Message: tt.msg}, fields)
+		require.NoError(t, err)
+		assert.Equal(t, "> "+tt.want, buf.String(), tt.policy)
+		buf.Free()
+	}
+}
+
+func TestQuotesUseKeyColour(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	buf, err := NewEncoder(cfg, WithColourDepth(Colours16), WithQuoting(QuoteAlways)).
+		EncodeEntry(zapcore.Entry{Level: zapcore.InfoLevel, Message: "m"}, []zapcore.Field{zap.String("k", "v")})
+	require.NoError(t, err)
+	defer buf.Free()
+	assert.Contains(t, tagANSI(buf.String()), `<green>k=<r><green>"<r>v<green>"<r>`)
+}
+
+func TestQuotingEnv(t *testing.T) {
+	t.Setenv("PRETTYCONSOLE_COLOUR", "off")
+	t.Setenv("PRETTYCONSOLE_QUOTING", "needed")
+	out, err := encodeRegistered(t, zap.String("k", ""))
+	require.NoError(t, err)
+	assert.Contains(t, out, `k=""`)
+
+	t.Setenv("PRETTYCONSOLE_QUOTING", "sometimes")
+	_, err = encodeRegistered(t)
+	assert.EqualError(t, err, `invalid PRETTYCONSOLE_QUOTING "sometimes": want never, needed or always`)
+}
+
+func TestNeedsQuotes(t *testing.T) {
+	for s, want := range map[string]bool{
+		"":        true,
+		" a":      true,
+		"a ":      true,
+		"a b":     true,
+		"a,b":     false,
+		"bird":    false,
+		"true":    true,
+		"null":    true,
+		"NaN":     true,
+		"-Inf":    true,
+		"42":      true,
+		"+4.2":    true,
+		"1e-9":    true,
+		".5":      true,
+		"1.2.3":   false,
+		"e5":      false,
+		"1e":      false,
+		"-":       false,
+		"0x1F":    false,
+		"v1.2":    false,
+		"Truthy":  false,
+		"12 apes": true,
+	} {
+		assert.Equal(t, want, needsQuotes(s, " "), "%q", s)
+		assert.Equal(t, want, needsQuotes([]byte(s), " "), "%q", s)
+	}
+	assert.True(t, needsQuotes("a,b", ", "))
+	assert.False(t, needsQuotes("a b", ", "))
+	assert.False(t, needsQuotes("a b", ""))
+}
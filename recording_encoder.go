@@ -33,9 +33,17 @@ type preparedContext struct {
 	// encoders, this means context fields are rendered once, not per
 	// line: marshalers and errors in accumulated context are frozen at
 	// first use.
-	rendered [len(defaultColours)]atomic.Pointer[[]byte]
+	rendered [renderedSlots]atomic.Pointer[[]byte]
 }
 
+const (
+	// renderedOffset and renderedSlots are the levels whose rendered
+	// context is cached: the DIY trace level up to FatalLevel and a little
+	// headroom. Rarer custom levels render theirs each time.
+	renderedOffset = 2
+	renderedSlots  = 10
+)
+
 // Clone implements zapcore.Encoder
 func (r *recordingEncoder) Clone() zapcore.Encoder {
 	clone := getRecordingEncoder()
@@ -65,10 +73,19 @@ func (r *recordingEncoder) prepared() *preparedContext {
 // renderedContext returns the cached rendering of the recorded fields for
 // a level, rendering and publishing it on first use.
 func (r *recordingEncoder) renderedContext(p *preparedContext, lvl zapcore.Level) []byte {
-	idx := colourIdx(lvl)
+	idx := int(lvl) + renderedOffset
+	if idx < 0 || idx >= renderedSlots {
+		return r.renderContext(p, lvl)
+	}
 	if b := p.rendered[idx].Load(); b != nil {
 		return *b
 	}
+	b := r.renderContext(p, lvl)
+	p.rendered[idx].CompareAndSwap(nil, &b)
+	return *p.rendered[idx].Load()
+}
+
+func (r *recordingEncoder) renderContext(p *preparedContext, lvl zapcore.Level) []byte {
 	enc := r.e
 	enc.buf = getBuffer()
 	enc.level = lvl
@@ -77,8 +94,7 @@ func (r *recordingEncoder) renderedContext(p *preparedContext, lvl zapcore.Level
 	enc.encodeFields(p.sorted)
 	b := append([]byte(nil), enc.buf.Bytes()...)
 	putBuffer(enc.buf)
-	p.rendered[idx].CompareAndSwap(nil, &b)
-	return *p.rendered[idx].Load()
+	return b
 }
 
 // EncodeEntry implements zapcore.Encoder
@@ -89,10 +105,11 @@ func (r *recordingEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Fie
 		return r.encodeMerged(entry, nil, fields)
 	}
 	p := r.prepared()
-	if len(fields) == 0 {
+	if len(fields) == 0 && !r.e.opts.values.relative {
 		// Fast path: nothing to interleave with the context, so reuse
-		// its cached rendering for this level. The pooled encoder keeps
-		// the copy from escaping through the preamble interfaces.
+		// its cached rendering for this level. Relative times change
+		// with every entry, so they are never cached. The pooled encoder
+		// keeps the copy from escaping through the preamble interfaces.
 		enc := getPrettyConsoleEncoder()
 		*enc = r.e
 		enc.buf = getBuffer()
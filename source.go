@@ -0,0 +1,126 @@
+package prettyconsole
+
+import (
+	"container/list"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// snippetContext is the number of lines shown either side of the line a
+// source snippet is for.
+const snippetContext = 2
+
+// sourceCacheSize is the number of files sourceCache keeps.
+const sourceCacheSize = 32
+
+// sourceCache maps a file path to its lines, or to nil if it could not be
+// read, so files that keep logging errors are not read from disk each
+// time. It keeps the most recently used sourceCacheSize files, so a
+// long-running process does not hold every file it ever showed.
+var sourceCache = struct {
+	sync.Mutex
+	files map[string]*list.Element
+	order list.List // of *sourceFile, most recently used first
+}{files: map[string]*list.Element{}}
+
+type sourceFile struct {
+	path  string
+	lines []string
+}
+
+func cachedSource(path string) []string {
+	c := &sourceCache
+	c.Lock()
+	if el, ok := c.files[path]; ok {
+		c.order.MoveToFront(el)
+		c.Unlock()
+		return el.Value.(*sourceFile).lines
+	}
+	c.Unlock()
+
+	var lines []string
+	if b, err := os.ReadFile(path); err == nil {
+		s := strings.TrimSuffix(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+		lines = strings.Split(s, "\n")
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if el, ok := c.files[path]; ok {
+		// Read concurrently; keep the first.
+		return el.Value.(*sourceFile).lines
+	}
+	c.files[path] = c.order.PushFront(&sourceFile{path: path, lines: lines})
+	if c.order.Len() > sourceCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.files, oldest.Value.(*sourceFile).path)
+	}
+	return lines
+}
+
+// sourceFor returns the cached lines of path if it has the given line.
+func sourceFor(path string, line int) ([]string, bool) {
+	if path == "" || line < 1 {
+		return nil, false
+	}
+	src := cachedSource(path)
+	return src, line <= len(src)
+}
+
+// addSourceSnippet writes the given line of src with snippetContext lines
+// either side, each numbered, and a caret under the start of the line
+// itself.
+func (e *prettyConsoleEncoder) addSourceSnippet(key string, src []string, line int) {
+	e.addSeparator()
+	e.addKey(key)
+	first, last := max(1, line-snippetContext), min(len(src), line+snippetContext)
+	width := digits(last)
+	pal := &e.opts.pal
+	gutter := func(n int, style string) {
+		e.buf.AppendString(style)
+		appendSpaces(e.buf, width-digits(n))
+		if n > 0 {
+			e.buf.AppendInt(int64(n))
+		} else {
+			appendSpaces(e.buf, 1)
+		}
+		e.buf.AppendString(" | ")
+		e.buf.AppendString(pal.reset)
+	}
+	for n := first; n <= last; n++ {
+		if n != first {
+			e.buf.AppendString(e.cfg.LineEnding)
+			appendSpaces(e.buf, e.namespaceIndent)
+		}
+		code := src[n-1]
+		if n != line {
+			gutter(n, pal.stackStd)
+			e.buf.AppendString(code)
+			continue
+		}
+		gutter(n, pal.level[colourIdx(e.level)])
+		e.buf.AppendString(code)
+		e.buf.AppendString(e.cfg.LineEnding)
+		appendSpaces(e.buf, e.namespaceIndent)
+		gutter(0, pal.stackStd)
+		// Reuse the line's own indentation, tabs included, so the caret
+		// lines up however the terminal renders tabs.
+		e.buf.AppendString(code[:len(code)-len(strings.TrimLeftFunc(code, unicode.IsSpace))])
+		e.colorizeAtLevel("^")
+	}
+
+	e.inList = true
+	e.setListSep(e._listSepSpace)
+}
+
+// digits returns the number of decimal digits in n, counting 0 as one.
+func digits(n int) int {
+	d := 1
+	for ; n >= 10; n /= 10 {
+		d++
+	}
+	return d
+}
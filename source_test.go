@@ -0,0 +1,119 @@
+package prettyconsole
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSourceSnippets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n\nfunc main() {\n\tif err := run(); err != nil {\n\t\tlog.Error(err)\n\t}\n}\n"), 0o644))
+
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	enc := NewEncoder(cfg, WithSourceSnippets(true), WithColour(false))
+	encode := func(ent zapcore.Entry) string {
+		ent.Message = "m"
+		buf, err := enc.EncodeEntry(ent, nil)
+		require.NoError(t, err)
+		defer buf.Free()
+		return buf.String()
+	}
+
+	assert.Equal(t, "ERR > m\n"+
+		"  ↳ source=3 | func main() {\n"+
+		"           4 | \tif err := run(); err != nil {\n"+
+		"           5 | \t\tlog.Error(err)\n"+
+		"             | \t\t^\n"+
+		"           6 | \t}\n"+
+		"           7 | }\n",
+		encode(zapcore.Entry{Level: zapcore.ErrorLevel, Caller: zapcore.NewEntryCaller(0, path, 5, true)}))
+
+	// Snippets are clipped to the file.
+	assert.Equal(t, "FTL > m\n"+
+		"  ↳ source=1 | package main\n"+
+		"             | ^\n"+
+		"           2 | \n"+
+		"           3 | func main() {\n",
+		encode(zapcore.Entry{Level: zapcore.FatalLevel, Caller: zapcore.NewEntryCaller(0, path, 1, true)}))
+
+	// The file is cached, so later changes are not seen.
+	require.NoError(t, os.WriteFile(path, []byte("changed"), 0o644))
+	assert.Contains(t, encode(zapcore.Entry{Level: zapcore.ErrorLevel, Caller: zapcore.NewEntryCaller(0, path, 7, true)}),
+		"           6 | \t}\n           7 | }\n             | ^\n")
+
+	// Lower levels, missing files and lines past the end are skipped.
+	for _, ent := range []zapcore.Entry{
+		{Level: zapcore.WarnLevel, Caller: zapcore.NewEntryCaller(0, path, 5, true)},
+		{Level: zapcore.ErrorLevel, Caller: zapcore.NewEntryCaller(0, path+".missing", 5, true)},
+		{Level: zapcore.ErrorLevel, Caller: zapcore.NewEntryCaller(0, path, 8, true)},
+		{Level: zapcore.ErrorLevel, Caller: zapcore.NewEntryCaller(0, path, 0, true)},
+	} {
+		assert.NotContains(t, encode(ent), "source=", "entry %+v", ent)
+	}
+}
+
+func TestSourceSnippetsForErrors(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	err := pkgerrors.New("boom") // The snippet points here.
+	fields := []zapcore.Field{zap.Error(err)}
+
+	buf, encErr := NewEncoder(cfg, WithSourceSnippets(true), WithColour(false)).EncodeEntry(
+		zapcore.Entry{Level: zapcore.ErrorLevel, Message: "m"}, fields)
+	require.NoError(t, encErr)
+	out := buf.String()
+	buf.Free()
+	_, snippet, ok := strings.Cut(out, "\n          .source=")
+	require.True(t, ok, out)
+	assert.Regexp(t, `^\d+ \| .*\n +\d+ \| .*\n +\d+ \| \terr := pkgerrors.New\("boom"\) // The snippet points here.\n +\| \t\^\n`, snippet)
+
+	buf, encErr = NewEncoder(cfg, WithSourceSnippets(true)).EncodeEntry(
+		zapcore.Entry{Level: zapcore.InfoLevel, Message: "m"}, fields)
+	require.NoError(t, encErr)
+	defer buf.Free()
+	assert.NotContains(t, buf.String(), "source=")
+}
+
+func TestDigits(t *testing.T) {
+	for n, want := range map[int]int{0: 1, 9: 1, 10: 2, 99: 2, 100: 3, 12345: 5} {
+		assert.Equal(t, want, digits(n), "digits(%d)", n)
+	}
+}
+
+func TestSourceCacheIsBounded(t *testing.T) {
+	dir := t.TempDir()
+	paths := make([]string, sourceCacheSize+5)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("f%d.go", i))
+		require.NoError(t, os.WriteFile(paths[i], []byte(fmt.Sprintf("line %d\n", i)), 0o600))
+	}
+	for i, p := range paths {
+		src, ok := sourceFor(p, 1)
+		require.True(t, ok)
+		assert.Equal(t, []string{fmt.Sprintf("line %d", i)}, src)
+	}
+
+	sourceCache.Lock()
+	n := sourceCache.order.Len()
+	_, oldest := sourceCache.files[paths[0]]
+	_, newest := sourceCache.files[paths[len(paths)-1]]
+	sourceCache.Unlock()
+	assert.Equal(t, sourceCacheSize, n)
+	assert.False(t, oldest, "the least recently used file is evicted")
+	assert.True(t, newest)
+
+	// Evicted files are read again.
+	require.NoError(t, os.WriteFile(paths[0], []byte("changed\n"), 0o600))
+	src, _ := sourceFor(paths[0], 1)
+	assert.Equal(t, []string{"changed"}, src)
+}
@@ -0,0 +1,213 @@
+package prettyconsole
+
+import (
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// mainModule is the path of the main module, or "" if the binary has no
+// build information.
+var mainModule = sync.OnceValue(func() string {
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		return buildInfo.Main.Path
+	}
+	return ""
+})
+
+// trimmedPaths reports whether the binary was built with -trimpath, so
+// its file paths are package paths such as
+// github.com/foo/bar/main.go rather than paths on disk.
+var trimmedPaths = sync.OnceValue(func() bool {
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range buildInfo.Settings {
+			if s.Key == "-trimpath" {
+				return s.Value == "true"
+			}
+		}
+	}
+	return false
+})
+
+// stackFrame is one frame of a stacktrace. location is "file:line".
+type stackFrame struct {
+	function string
+	location string
+}
+
+// parseStack parses a stacktrace in the format zap captures for
+// entry.Stack: a function line and a tab-indented file:line line per
+// frame. It reports false for anything else, which is then printed as is.
+func parseStack(s string) ([]stackFrame, bool) {
+	s = strings.TrimPrefix(s, "\n")
+	var frames []stackFrame
+	for s != "" {
+		var fn, loc string
+		fn, s, _ = strings.Cut(s, "\n")
+		loc, s, _ = strings.Cut(s, "\n")
+		if fn == "" || fn[0] == '\t' || !strings.HasPrefix(loc, "\t") {
+			return nil, false
+		}
+		frames = append(frames, stackFrame{function: fn, location: loc[1:]})
+	}
+	return frames, len(frames) > 0
+}
+
+// pkgErrorsFrames resolves a github.com/pkg/errors stacktrace, as its own
+// %+v formatting would.
+func pkgErrorsFrames(st errors.StackTrace) []stackFrame {
+	frames := make([]stackFrame, len(st))
+	for i, f := range st {
+		fn, file, line := frameInfo(f)
+		frames[i] = stackFrame{function: fn, location: file + ":" + strconv.Itoa(line)}
+	}
+	return frames
+}
+
+// frameInfo resolves a github.com/pkg/errors frame, reporting "unknown" for
+// anything the runtime cannot, as pkg/errors itself does.
+func frameInfo(f errors.Frame) (function, file string, line int) {
+	pc := uintptr(f) - 1
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown", "unknown", 0
+	}
+	file, line = fn.FileLine(pc)
+	return fn.Name(), file, line
+}
+
+// trimParentFrames splits st into the frames unique to it and the number of
+// outermost frames it shares with parent. A wrapped error is usually
+// created further down the same call stack as its wrapper, so everything
+// from the common caller outwards would otherwise be printed at every
+// level of the chain.
+func trimParentFrames(st, parent errors.StackTrace) (errors.StackTrace, int) {
+	common := 0
+	for common < len(st) && common < len(parent) &&
+		st[len(st)-1-common] == parent[len(parent)-1-common] {
+		common++
+	}
+	return st[:len(st)-common], common
+}
+
+// isMainFunc reports whether a function belongs to the main module.
+func isMainFunc(fn string) bool {
+	m := mainModule()
+	return m != "" && strings.HasPrefix(fn, m) && len(fn) > len(m) && (fn[len(m)] == '.' || fn[len(m)] == '/')
+}
+
+// isStdFunc reports whether a function belongs to the standard library,
+// whose import paths have no dot in their first element.
+func isStdFunc(fn string) bool {
+	if i := strings.IndexByte(fn, '/'); i >= 0 {
+		return !strings.Contains(fn[:i], ".")
+	}
+	pkg, _, _ := strings.Cut(fn, ".")
+	return pkg != "main"
+}
+
+// hideFrame reports whether a function matches one of the WithHiddenFrames
+// patterns.
+func (o *options) hideFrame(fn string) bool {
+	for _, p := range o.hiddenFrames {
+		if ok, _ := path.Match(p, fn); ok {
+			return true
+		}
+		if pkg, ok := strings.CutSuffix(p, "/..."); ok && inPackageTree(pkg, fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// inPackageTree reports whether fn is in a package matching the pattern
+// pkg, or in a package below one.
+func inPackageTree(pkg, fn string) bool {
+	if ok, _ := path.Match(pkg+".*", fn); ok {
+		return true
+	}
+	// Match pkg against as many path elements of fn as it has.
+	end := -1
+	for n := strings.Count(pkg, "/") + 1; n > 0; n-- {
+		i := strings.IndexByte(fn[end+1:], '/')
+		if i < 0 {
+			return false
+		}
+		end += i + 1
+	}
+	ok, _ := path.Match(pkg, fn[:end])
+	return ok
+}
+
+// addStackFrames writes one line per frame, styled by where the frame comes
+// from. Runs of hidden frames, and the common frames trimmed by
+// trimParentFrames, are each summarised on a single line.
+func (e *prettyConsoleEncoder) addStackFrames(key string, frames []stackFrame, common int) {
+	e.addSeparator()
+	e.addKey(key)
+	pal := &e.opts.pal
+	first := true
+	newLine := func() {
+		if !first {
+			e.buf.AppendString(e.cfg.LineEnding)
+			appendSpaces(e.buf, e.namespaceIndent)
+		}
+		first = false
+	}
+	summary := func(n int, what string) {
+		newLine()
+		e.buf.AppendString(pal.stackStd)
+		e.buf.AppendString("... ")
+		e.buf.AppendInt(int64(n))
+		e.buf.AppendString(what)
+		e.buf.AppendString(pal.reset)
+	}
+
+	hidden := 0
+	for _, f := range frames {
+		if e.opts.hideFrame(f.function) {
+			hidden++
+			continue
+		}
+		if hidden > 0 {
+			summary(hidden, plural(hidden, " hidden frame"))
+			hidden = 0
+		}
+		newLine()
+		var style string
+		switch {
+		case isMainFunc(f.function):
+			style = pal.stackMain
+		case isStdFunc(f.function):
+			style = pal.stackStd
+		}
+		e.buf.AppendString(style)
+		e.buf.AppendString(f.function)
+		e.buf.AppendByte(' ')
+		e.appendLocation(f.location)
+		if style != "" {
+			e.buf.AppendString(pal.reset)
+		}
+	}
+	if hidden > 0 {
+		summary(hidden, plural(hidden, " hidden frame"))
+	}
+	if common > 0 {
+		summary(common, plural(common, " frame")+" in common with parent")
+	}
+
+	e.inList = true
+	e.setListSep(e._listSepSpace)
+}
+
+func plural(n int, s string) string {
+	if n == 1 {
+		return s
+	}
+	return s + "s"
+}
@@ -0,0 +1,155 @@
+package prettyconsole
+
+import (
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseStack(t *testing.T) {
+	frames, ok := parseStack("main.main\n\t/src/main.go:10\nruntime.main\n\t/go/src/runtime/proc.go:283")
+	assert.True(t, ok)
+	assert.Equal(t, []stackFrame{
+		{function: "main.main", location: "/src/main.go:10"},
+		{function: "runtime.main", location: "/go/src/runtime/proc.go:283"},
+	}, frames)
+
+	// pkg/errors' %+v output starts with a newline.
+	frames, ok = parseStack("\nmain.main\n\t/src/main.go:10")
+	assert.True(t, ok)
+	assert.Len(t, frames, 1)
+
+	for _, s := range []string{
+		"",
+		"not a stack",
+		"main.main\n/src/main.go:10",
+		"\t/src/main.go:10\nmain.main",
+		"main.main\n\t/src/main.go:10\nruntime.main",
+		"goroutine 1 [running]:\nmain.main()\n\t/src/main.go:10 +0x1d",
+	} {
+		_, ok := parseStack(s)
+		assert.False(t, ok, "stack %q", s)
+	}
+}
+
+func TestTrimParentFrames(t *testing.T) {
+	st := pkgerrors.StackTrace{1, 2, 3, 4}
+	for _, tt := range []struct {
+		parent pkgerrors.StackTrace
+		unique pkgerrors.StackTrace
+		common int
+	}{
+		{parent: nil, unique: st},
+		{parent: pkgerrors.StackTrace{5, 6}, unique: st},
+		{parent: pkgerrors.StackTrace{9, 3, 4}, unique: st[:2], common: 2},
+		{parent: pkgerrors.StackTrace{0, 1, 2, 3, 4}, unique: st[:0], common: 4},
+	} {
+		unique, common := trimParentFrames(st, tt.parent)
+		assert.Equal(t, tt.unique, unique, "parent %v", tt.parent)
+		assert.Equal(t, tt.common, common, "parent %v", tt.parent)
+	}
+}
+
+func TestFrameOrigin(t *testing.T) {
+	require.Equal(t, "github.com/thessem/zap-prettyconsole", mainModule())
+	for fn, want := range map[string][2]bool{
+		"github.com/thessem/zap-prettyconsole.TestFrameOrigin":     {true, false},
+		"github.com/thessem/zap-prettyconsole/cmd/zap-pretty.main": {true, false},
+		"github.com/thessem/zap-prettyconsole-fork.F":              {false, false},
+		"runtime.goexit":                         {false, true},
+		"testing.tRunner.func1":                  {false, true},
+		"net/http.(*conn).serve":                 {false, true},
+		"main.main":                              {false, false},
+		"go.uber.org/zap.(*Logger).Error":        {false, false},
+		"golang.org/x/sync/errgroup.(*Group).Go": {false, false},
+	} {
+		assert.Equal(t, want[0], isMainFunc(fn), "isMainFunc(%q)", fn)
+		assert.Equal(t, want[1], isStdFunc(fn), "isStdFunc(%q)", fn)
+	}
+}
+
+func TestStackFrames(t *testing.T) {
+	stack := "github.com/thessem/zap-prettyconsole.handler\n\t/src/handler.go:10\n" +
+		"go.uber.org/zap.(*Logger).Error\n\t/mod/zap/logger.go:20\n" +
+		"net/http.HandlerFunc.ServeHTTP\n\t/go/src/net/http/server.go:30\n" +
+		"net/http.(*conn).serve\n\t/go/src/net/http/server.go:40\n" +
+		"testing.tRunner\n\t/go/src/testing/testing.go:50\n" +
+		"runtime.goexit\n\t/go/src/runtime/asm_amd64.s:60"
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	ent := zapcore.Entry{Level: zapcore.ErrorLevel, Message: "m", Stack: stack}
+
+	tests := []struct {
+		desc string
+		opts []Option
+		want string
+	}{
+		{
+			desc: "All",
+			want: "<red>  ↳ <r><red>stacktrace=<r><bold>github.com/thessem/zap-prettyconsole.handler /src/handler.go:10<r>\n" +
+				"               go.uber.org/zap.(*Logger).Error /mod/zap/logger.go:20\n" +
+				"               <esc:2>net/http.HandlerFunc.ServeHTTP /go/src/net/http/server.go:30<r>\n" +
+				"               <esc:2>net/http.(*conn).serve /go/src/net/http/server.go:40<r>\n" +
+				"               <esc:2>testing.tRunner /go/src/testing/testing.go:50<r>\n" +
+				"               <esc:2>runtime.goexit /go/src/runtime/asm_amd64.s:60<r>\n",
+		},
+		{
+			desc: "Hidden",
+			opts: []Option{WithHiddenFrames("net/http.*", "[", "runtime.*")},
+			want: "<red>  ↳ <r><red>stacktrace=<r><bold>github.com/thessem/zap-prettyconsole.handler /src/handler.go:10<r>\n" +
+				"               go.uber.org/zap.(*Logger).Error /mod/zap/logger.go:20\n" +
+				"               <esc:2>... 2 hidden frames<r>\n" +
+				"               <esc:2>testing.tRunner /go/src/testing/testing.go:50<r>\n" +
+				"               <esc:2>... 1 hidden frame<r>\n",
+		},
+		{
+			desc: "Monochrome",
+			opts: []Option{WithColour(false), WithHiddenFrames("github.com/*/*", "go.uber.org/*")},
+			want: "  ↳ stacktrace=... 2 hidden frames\n" +
+				"               net/http.HandlerFunc.ServeHTTP /go/src/net/http/server.go:30\n" +
+				"               net/http.(*conn).serve /go/src/net/http/server.go:40\n" +
+				"               testing.tRunner /go/src/testing/testing.go:50\n" +
+				"               runtime.goexit /go/src/runtime/asm_amd64.s:60\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			buf, err := NewEncoder(cfg, tt.opts...).EncodeEntry(ent, nil)
+			require.NoError(t, err)
+			defer buf.Free()
+			_, got, _ := strings.Cut(tagANSI(buf.String()), "\n")
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHideFrameNestedPackages(t *testing.T) {
+	o := newOptions([]Option{WithHiddenFrames("github.com/foo/*", "go.uber.org/...", "example.com/*/internal/...")})
+	for fn, want := range map[string]bool{
+		"github.com/foo/bar.Fn":               true,
+		"github.com/foo/bar/baz.Fn":           false, // * stops at /
+		"go.uber.org/zap.(*Logger).Error":     true,
+		"go.uber.org/zap/zapcore.(*ioCore).W": true,
+		"go.uber.org.Fn":                      true,
+		"go.uber.orgx/zap.Fn":                 false,
+		"example.com/a/internal.Fn":           true,
+		"example.com/a/internal/deep/er.Fn":   true,
+		"example.com/a/public.Fn":             false,
+		"example.com.Fn":                      false,
+	} {
+		assert.Equal(t, want, o.hideFrame(fn), fn)
+	}
+}
+
+func TestStackFramesUnparsed(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	buf, err := NewEncoder(cfg, WithColour(false)).EncodeEntry(zapcore.Entry{Message: "m", Stack: "free\nform"}, nil)
+	require.NoError(t, err)
+	defer buf.Free()
+	assert.Equal(t, "INF > m\n  ↳ stacktrace=free\n               form\n", buf.String())
+}
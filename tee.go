@@ -0,0 +1,32 @@
+package prettyconsole
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewTeeCore returns a core writing every entry twice: pretty output to
+// console and JSON lines to file, both filtered by enab. The JSON uses
+// zap's production encoder config, and the pretty output the same keys,
+// so the file can be replayed through the pretty encoder later with the
+// zap-pretty command or a JSONDecoder.
+//
+// Each side is a plain zapcore.NewCore, so context added with With is
+// still rendered once and cached by the pretty encoder.
+func NewTeeCore(console, file zapcore.WriteSyncer, enab zapcore.LevelEnabler, opts ...Option) zapcore.Core {
+	jsonCfg := zap.NewProductionEncoderConfig()
+	return zapcore.NewTee(
+		zapcore.NewCore(NewEncoder(encoderConfigWithKeys(jsonCfg), opts...), console, enab),
+		zapcore.NewCore(zapcore.NewJSONEncoder(jsonCfg), file, enab),
+	)
+}
+
+// NewTeeLogger returns a logger writing pretty output to stdout, coloured
+// when stdout should be (see ColourEnabled), and JSON lines to file. See
+// NewTeeCore.
+func NewTeeLogger(lvl zapcore.Level, file zapcore.WriteSyncer, opts ...Option) *zap.Logger {
+	opts = append([]Option{WithColour(ColourEnabled(os.Stdout))}, opts...)
+	return zap.New(NewTeeCore(os.Stdout, file, lvl, opts...))
+}
@@ -0,0 +1,63 @@
+package prettyconsole
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestTeeCore(t *testing.T) {
+	var console, file bytes.Buffer
+	logger := zap.New(NewTeeCore(zapcore.AddSync(&console), zapcore.AddSync(&file), zapcore.InfoLevel, WithColour(false)))
+	logger = logger.Named("svc").With(zap.String("ctx", "c"), zap.Object("obj", testStableMap{"a": 1}))
+	logger.Debug("hidden")
+	logger.Info("first", zap.Int("n", 1))
+	logger.Warn("second", zap.Strings("list", []string{"x", "y"}))
+
+	consoleLines := strings.SplitAfter(console.String(), "\n")
+	fileLines := strings.Split(strings.TrimSpace(file.String()), "\n")
+	require.Len(t, fileLines, 2)
+	assert.NotContains(t, console.String(), "hidden")
+	assert.Regexp(t, `^\d+:\d\d[AP]M INF svc > first ctx=c n=1\n$`, consoleLines[0])
+	assert.Equal(t, "  ↳ obj.a=1\n", consoleLines[1])
+
+	// Replaying the JSON file through the pretty encoder reproduces the
+	// console output.
+	dec := NewJSONDecoder(zap.NewProductionEncoderConfig())
+	enc := NewEncoder(encoderConfigWithKeys(zap.NewProductionEncoderConfig()), WithColour(false))
+	var replayed strings.Builder
+	for _, line := range fileLines {
+		ent, fields, err := dec.Decode([]byte(line))
+		require.NoError(t, err)
+		buf, err := enc.EncodeEntry(ent, fields)
+		require.NoError(t, err)
+		replayed.WriteString(buf.String())
+		buf.Free()
+	}
+	assert.Equal(t, console.String(), replayed.String())
+}
+
+func TestTeeCoreSharesLevelEnabler(t *testing.T) {
+	var console, file bytes.Buffer
+	lvl := zap.NewAtomicLevelAt(zapcore.ErrorLevel)
+	logger := zap.New(NewTeeCore(zapcore.AddSync(&console), zapcore.AddSync(&file), lvl))
+	logger.Warn("dropped")
+	lvl.SetLevel(zapcore.WarnLevel)
+	logger.Warn("kept")
+
+	assert.NotContains(t, console.String(), "dropped")
+	assert.NotContains(t, file.String(), "dropped")
+	assert.Contains(t, console.String(), "kept")
+	assert.Contains(t, file.String(), `"msg":"kept"`)
+}
+
+func TestNewTeeLogger(t *testing.T) {
+	var file bytes.Buffer
+	NewTeeLogger(zapcore.InfoLevel, zapcore.AddSync(&file)).Info("tee smoke test")
+	assert.Contains(t, file.String(), `"msg":"tee smoke test"`)
+}
@@ -0,0 +1,37 @@
+package prettyconsole
+
+import (
+	"io"
+	"os"
+)
+
+// ColourEnabled reports whether output written to w should be coloured.
+//
+// A non-empty NO_COLOR environment variable (https://no-color.org) always
+// disables colour. Otherwise FORCE_COLOR, when set, enables colour unless it
+// is "0" or "false". Without either, colour is enabled only when w is a
+// terminal.
+func ColourEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if v, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		return v != "0" && v != "false"
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a character device. This is the
+// dependency-free approximation of an isatty check: it also accepts
+// devices like /dev/null, where colour is harmless anyway.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
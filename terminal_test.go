@@ -0,0 +1,102 @@
+package prettyconsole
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestColourEnabled(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+
+	tests := []struct {
+		desc            string
+		noColour, force string
+		setNo, setForce bool
+		out             io.Writer
+		want            bool
+	}{
+		{desc: "pipe", out: w, want: false},
+		{desc: "buffer", out: &bytes.Buffer{}, want: false},
+		{desc: "forced", setForce: true, force: "1", out: w, want: true},
+		{desc: "forced empty", setForce: true, force: "", out: w, want: true},
+		{desc: "forced off", setForce: true, force: "0", out: w, want: false},
+		{desc: "forced false", setForce: true, force: "false", out: w, want: false},
+		{desc: "no colour wins", setNo: true, noColour: "1", setForce: true, force: "1", out: w, want: false},
+		{desc: "empty no colour ignored", setNo: true, noColour: "", setForce: true, force: "1", out: w, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColour)
+			t.Setenv("FORCE_COLOR", tt.force)
+			if !tt.setNo {
+				require.NoError(t, os.Unsetenv("NO_COLOR"))
+			}
+			if !tt.setForce {
+				require.NoError(t, os.Unsetenv("FORCE_COLOR"))
+			}
+			assert.Equal(t, tt.want, ColourEnabled(tt.out))
+		})
+	}
+}
+
+// TestMonochromeMatchesStrippedColour checks the monochrome palette keeps
+// the exact layout of coloured output, just without escape sequences.
+func TestMonochromeMatchesStrippedColour(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.CallerKey = "C"
+	ent := zapcore.Entry{
+		Level:      zapcore.ErrorLevel,
+		Time:       time.Date(2018, 6, 19, 16, 33, 42, 99, time.UTC),
+		LoggerName: "name",
+		Message:    "multi\nline",
+		Caller:     zapcore.NewEntryCaller(0, "/path/to/foo.go", 42, true),
+		Stack:      "main.main\n\t/path/to/main.go:1",
+	}
+	fields := []zapcore.Field{
+		zap.String("s", "tab\there"),
+		zap.Strings("l", []string{"a", "b"}),
+		zap.Object("o", testStableMap{"k": "v", "n": testStableMap{"x": 1}}),
+		zap.Array("a", testArray{1, testArray{2, 3}, testStableMap{"k": "v"}}),
+		zap.Error(pkgerrors.Wrap(fmt.Errorf("cause"), "wrapped")),
+		zap.Namespace("ns"),
+		zap.Int("i", 1),
+	}
+	for _, lvl := range []zapcore.Level{zapcore.DebugLevel - 1, zapcore.InfoLevel, zapcore.ErrorLevel, zapcore.Level(42)} {
+		ent.Level = lvl
+		coloured, err := NewEncoder(cfg).EncodeEntry(ent, fields)
+		require.NoError(t, err)
+		plain, err := NewEncoder(cfg, WithColour(false)).EncodeEntry(ent, fields)
+		require.NoError(t, err)
+		assert.NotContains(t, plain.String(), "\x1b")
+		assert.Equal(t, stripANSI(coloured.String()), plain.String())
+		coloured.Free()
+		plain.Free()
+	}
+}
+
+func TestMonochromeRegisteredEncoding(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Encoding = "pretty_console_monochrome"
+	cfg.OutputPaths = []string{"stdout"}
+	_, err := cfg.Build()
+	require.NoError(t, err)
+
+	t.Setenv("NO_COLOR", "1")
+	assert.Equal(t, "pretty_console_monochrome", NewConfig().Encoding)
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "1")
+	assert.Equal(t, "pretty_console", NewConfig().Encoding)
+}
@@ -0,0 +1,211 @@
+package prettyconsole
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// Style is a colour plus text attributes.
+type Style struct {
+	Colour Colour
+	Bold   bool
+	Dim    bool
+}
+
+// prefix returns the escape sequences switching the terminal to this style,
+// with its colour reduced to the given depth. Codes are emitted one
+// sequence each, colour first.
+func (s Style) prefix(depth ColourDepth) string {
+	p := s.Colour.downsample(depth).sgr()
+	if s.Bold {
+		p += ansiBold
+	}
+	if s.Dim {
+		p += ansiDim
+	}
+	return p
+}
+
+// Theme describes the colours and attributes the encoder renders with.
+//
+// The structural elements (keys, separators, namespace arrows) are
+// coloured like their entry's level by default: leaving their Style as the
+// zero value keeps that behaviour.
+type Theme struct {
+	// Levels holds the style of each level's label. It is also used for
+	// the structure of entries at that level: brackets, escape sequences
+	// and any element below left unset. Levels without a style, here or
+	// given to RegisterLevel, keep their labels but take PanicLevel's
+	// style.
+	Levels map[zapcore.Level]Style
+	// Key styles field keys and their "=".
+	Key Style
+	// Separator styles the separators between fields and list elements.
+	Separator Style
+	// NamespaceArrow styles the "↳" that starts each namespace line.
+	NamespaceArrow Style
+	// Time styles the entry timestamp written by DefaultTimeEncoder.
+	Time Style
+	// Name styles logger names and callers.
+	Name Style
+	// StackMain styles stacktrace frames from the main module.
+	StackMain Style
+	// StackStd styles stacktrace frames from the standard library,
+	// including the runtime, and the lines summarising collapsed frames.
+	StackStd Style
+	// SlowDelta styles the time since the previous entry, written by
+	// DeltaTimeEncoder, when it is over the encoder's threshold.
+	SlowDelta Style
+}
+
+// DefaultTheme returns the theme the encoder uses unless told otherwise,
+// designed for dark terminal backgrounds. Each call returns a fresh copy
+// that is safe to modify.
+func DefaultTheme() Theme {
+	return Theme{
+		Levels: map[zapcore.Level]Style{
+			TraceLevel:          {Colour: BrightBlack},
+			zapcore.DebugLevel:  {Colour: Cyan},
+			zapcore.InfoLevel:   {Colour: Green},
+			zapcore.WarnLevel:   {Colour: Yellow},
+			zapcore.ErrorLevel:  {Colour: Red},
+			zapcore.DPanicLevel: {Colour: Red, Bold: true},
+			zapcore.PanicLevel:  {Colour: Red, Bold: true},
+			zapcore.FatalLevel:  {Colour: Red, Bold: true},
+		},
+		Time:      Style{Colour: BrightBlack},
+		Name:      Style{Bold: true},
+		StackMain: Style{Bold: true},
+		StackStd:  Style{Dim: true},
+		SlowDelta: Style{Colour: Yellow},
+	}
+}
+
+// LightTheme returns a theme for light terminal backgrounds, avoiding the
+// pale greens, yellows and cyans of DefaultTheme. Each call returns a fresh
+// copy that is safe to modify.
+func LightTheme() Theme {
+	return Theme{
+		Levels: map[zapcore.Level]Style{
+			TraceLevel:          {Colour: BrightBlack},
+			zapcore.DebugLevel:  {Colour: Blue},
+			zapcore.InfoLevel:   {Colour: Black},
+			zapcore.WarnLevel:   {Colour: Magenta},
+			zapcore.ErrorLevel:  {Colour: Red},
+			zapcore.DPanicLevel: {Colour: Red, Bold: true},
+			zapcore.PanicLevel:  {Colour: Red, Bold: true},
+			zapcore.FatalLevel:  {Colour: Red, Bold: true},
+		},
+		Time:      Style{Colour: BrightBlack},
+		Name:      Style{Bold: true},
+		StackMain: Style{Bold: true},
+		StackStd:  Style{Dim: true},
+		SlowDelta: Style{Colour: Magenta},
+	}
+}
+
+const (
+	// levelOffset is added to a level to find its slot in the per-level
+	// tables, which cover every zapcore.Level: it is an int8, and custom
+	// levels are legal.
+	levelOffset = 128
+	levelSlots  = 256
+)
+
+// palette is a Theme compiled into ready-to-append escape sequences, so the
+// hot path appends precomputed strings instead of assembling codes.
+type palette struct {
+	level [levelSlots]string
+	key   [levelSlots]string
+	sep   [levelSlots]string
+	arrow [levelSlots]string
+	// label holds the fully coloured label for each level, so encoding a
+	// level is a single append.
+	label        [levelSlots]string
+	unknownLabel string
+
+	time      string
+	name      string
+	stackMain string
+	stackStd  string
+	slowDelta string
+	bold      string
+	reset     string
+}
+
+// newPalette compiles a theme. Without colour every escape sequence is
+// empty, so the same append paths produce plain text with an identical
+// layout.
+func newPalette(o *options) palette {
+	var p palette
+	t := o.theme
+	// prefix returns the escape sequences for s, or inherit if s is unset.
+	prefix := func(s Style, inherit string) string {
+		if !o.colour {
+			return ""
+		}
+		if s == (Style{}) {
+			return inherit
+		}
+		return s.prefix(o.depth)
+	}
+	if o.colour {
+		p.bold, p.reset = ansiBold, ansiReset
+	}
+	p.time, p.name = prefix(t.Time, ""), prefix(t.Name, "")
+	p.stackMain, p.stackStd = prefix(t.StackMain, ""), prefix(t.StackStd, "")
+	p.slowDelta = prefix(t.SlowDelta, "")
+
+	// Levels without labels are written as ???, and levels without a
+	// style in PanicLevel's colours; the two are independent. Most slots
+	// have neither, so their strings are built once and shared.
+	unknown := prefix(t.Levels[zapcore.PanicLevel], "")
+	p.unknownLabel = unknown + "???" + p.reset + labelPadding("???", o.levelWidth)
+	unknownKey := prefix(t.Key, unknown)
+	unknownSep := prefix(t.Separator, unknown)
+	unknownArrow := prefix(t.NamespaceArrow, unknown)
+	levels := registeredLevels()
+	for i := range p.level {
+		l := zapcore.Level(i - levelOffset)
+		info, known := levels[l]
+		s, ok := t.Levels[l]
+		if !ok && info.style != (Style{}) {
+			s, ok = info.style, true
+		}
+		if !ok && !known {
+			p.level[i], p.label[i] = unknown, p.unknownLabel
+			p.key[i], p.sep[i], p.arrow[i] = unknownKey, unknownSep, unknownArrow
+			continue
+		}
+		p.level[i] = unknown
+		if ok {
+			p.level[i] = prefix(s, "")
+		}
+		p.label[i] = p.unknownLabel
+		if known {
+			label := info.labels.label(o.levelStyle)
+			p.label[i] = p.level[i] + label + p.reset + labelPadding(label, o.levelWidth)
+		}
+		p.key[i] = prefix(t.Key, p.level[i])
+		p.sep[i] = prefix(t.Separator, p.level[i])
+		p.arrow[i] = prefix(t.NamespaceArrow, p.level[i])
+	}
+	return p
+}
+
+// colourIdx maps a level to its palette slot.
+func colourIdx(l zapcore.Level) int {
+	return int(l) + levelOffset
+}
+
+// labelFor returns the coloured label for a level.
+func (p *palette) labelFor(l zapcore.Level) string {
+	return p.label[colourIdx(l)]
+}
+
+// timeStyle returns the style of timestamps, or of slow deltas.
+func (p *palette) timeStyle(slow bool) string {
+	if slow {
+		return p.slowDelta
+	}
+	return p.time
+}
@@ -0,0 +1,91 @@
+package prettyconsole
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func encodeThemed(t *testing.T, theme Theme, ent zapcore.Entry, fields ...zapcore.Field) string {
+	t.Helper()
+	cfg := NewEncoderConfig()
+	buf, err := NewEncoder(cfg, WithTheme(theme)).EncodeEntry(ent, fields)
+	require.NoError(t, err)
+	defer buf.Free()
+	return tagANSI(buf.String())
+}
+
+func TestDefaultThemeMatchesNoOptions(t *testing.T) {
+	ent := zapcore.Entry{Level: zapcore.WarnLevel, Message: "m", Time: time.Unix(0, 0).UTC(), LoggerName: "name"}
+	fields := []zapcore.Field{zap.String("k", "v"), zap.Namespace("ns"), zap.Int("i", 1)}
+
+	buf, err := NewEncoder(NewEncoderConfig()).EncodeEntry(ent, fields)
+	require.NoError(t, err)
+	defer buf.Free()
+	assert.Equal(t, tagANSI(buf.String()), encodeThemed(t, DefaultTheme(), ent, fields...))
+}
+
+func TestThemeElements(t *testing.T) {
+	theme := DefaultTheme()
+	theme.Levels[zapcore.InfoLevel] = Style{Colour: Blue}
+	theme.Key = Style{Colour: Magenta}
+	theme.Separator = Style{Colour: White}
+	theme.NamespaceArrow = Style{Colour: Yellow, Bold: true}
+	theme.Time = Style{Colour: Cyan, Dim: true}
+	theme.Name = Style{Colour: Red}
+
+	ent := zapcore.Entry{Level: zapcore.InfoLevel, Message: "m", Time: time.Unix(0, 0).UTC(), LoggerName: "name"}
+	out := encodeThemed(t, theme, ent, zap.String("k", "v"), zap.Namespace("ns"), zap.Strings("l", []string{"a", "b"}))
+
+	assert.Contains(t, out, "<cyan><esc:2>")
+	assert.Contains(t, out, "<esc:34>INF<r>")
+	assert.Contains(t, out, "<red>name<r>")
+	assert.Contains(t, out, "<esc:35>k=<r>v")
+	assert.Contains(t, out, "<esc:37> <r>")
+	assert.Contains(t, out, "<yellow><bold>  ↳ <r><esc:35>ns<r>")
+	assert.Contains(t, out, "a<esc:37>, <r>b", "list separators use the separator style")
+	assert.Contains(t, out, "<esc:34>]<r>", "brackets follow the level style")
+}
+
+func TestThemeUnknownLevels(t *testing.T) {
+	theme := Theme{Levels: map[zapcore.Level]Style{
+		zapcore.InfoLevel:  {Colour: Green},
+		zapcore.PanicLevel: {Colour: Magenta},
+	}}
+	out := encodeThemed(t, theme, zapcore.Entry{Level: zapcore.WarnLevel, Message: "m"})
+	assert.Contains(t, out, "<esc:35>WRN<r>", "levels missing from the theme are coloured like panics")
+	out = encodeThemed(t, theme, zapcore.Entry{Level: zapcore.Level(100), Message: "m"})
+	assert.Contains(t, out, "<esc:35>???<r>")
+}
+
+func TestThemePartial(t *testing.T) {
+	// A theme that only sets some styles keeps every level's label.
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	enc := NewEncoder(cfg, WithColour(false), WithTheme(Theme{Key: Style{Colour: Blue}}))
+	assert.Equal(t, "INF > m\n", encodeLevel(t, enc, zapcore.InfoLevel))
+
+	theme := Theme{Levels: map[zapcore.Level]Style{zapcore.ErrorLevel: {Colour: Blue}}}
+	for l, want := range map[zapcore.Level]string{
+		zapcore.DebugLevel: "<r>DBG<r>",
+		zapcore.InfoLevel:  "<r>INF<r>",
+		zapcore.ErrorLevel: "<esc:34>ERR<r>",
+	} {
+		out := encodeThemed(t, theme, zapcore.Entry{Level: l, Message: "m"})
+		assert.Contains(t, out, want, l)
+		assert.NotContains(t, out, "???", l)
+	}
+}
+
+func TestThemeConstructorsReturnCopies(t *testing.T) {
+	theme := DefaultTheme()
+	theme.Levels[zapcore.InfoLevel] = Style{Colour: Blue}
+	assert.Equal(t, Style{Colour: Green}, DefaultTheme().Levels[zapcore.InfoLevel])
+
+	out := encodeThemed(t, LightTheme(), zapcore.Entry{Level: zapcore.InfoLevel, Message: "m"})
+	assert.Contains(t, out, "<esc:30>INF<r>")
+}
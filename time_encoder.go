@@ -0,0 +1,150 @@
+package prettyconsole
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// ElapsedTimeEncoder returns a time encoder writing the time since start
+// with millisecond precision, as in +12.345s, in place of the wall clock.
+// Pass the time the logger was created to see how far into the run each
+// entry was logged.
+func ElapsedTimeEncoder(start time.Time) zapcore.TimeEncoder {
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		appendTime(enc, false, func(buf *buffer.Buffer) {
+			d := t.Sub(start)
+			if d < 0 {
+				buf.AppendByte('-')
+				d = -d
+			} else {
+				buf.AppendByte('+')
+			}
+			appendSeconds(buf, d)
+		})
+	}
+}
+
+// DeltaTimeEncoder returns a time encoder writing the time since the
+// previous entry, as in Δ150ms, in place of the wall clock. Deltas over
+// threshold are written in the theme's SlowDelta style; a threshold of 0
+// never highlights.
+//
+// The previous entry is the latest one encoded by any encoder using the
+// same time encoder, so each logger should be given its own. The first
+// entry's delta is from when DeltaTimeEncoder was called. When goroutines
+// log concurrently, entries can be encoded out of order; an entry older
+// than the latest one has a delta of 0.
+func DeltaTimeEncoder(threshold time.Duration) zapcore.TimeEncoder {
+	var latest atomic.Int64
+	latest.Store(time.Now().UnixNano())
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		now := t.UnixNano()
+		prev := latest.Load()
+		for now > prev && !latest.CompareAndSwap(prev, now) {
+			prev = latest.Load()
+		}
+		d := time.Duration(max(now-prev, 0))
+		appendTime(enc, threshold > 0 && d > threshold, func(buf *buffer.Buffer) {
+			buf.AppendString("Δ")
+			appendDuration(buf, d)
+		})
+	}
+}
+
+// appendDuration writes a non-negative duration in its largest unit, down
+// to microseconds: 1.234s, 150ms or 12µs.
+func appendDuration(buf *buffer.Buffer, d time.Duration) {
+	switch {
+	case d >= time.Second:
+		appendSeconds(buf, d)
+	case d >= time.Millisecond:
+		buf.AppendInt(d.Milliseconds())
+		buf.AppendString("ms")
+	default:
+		buf.AppendInt(d.Microseconds())
+		buf.AppendString("µs")
+	}
+}
+
+// appendSeconds writes a non-negative duration in seconds, to the
+// millisecond.
+func appendSeconds(buf *buffer.Buffer, d time.Duration) {
+	ms := d.Milliseconds()
+	buf.AppendInt(ms / 1000)
+	buf.AppendByte('.')
+	frac := ms % 1000
+	if frac < 100 {
+		buf.AppendByte('0')
+	}
+	if frac < 10 {
+		buf.AppendByte('0')
+	}
+	buf.AppendInt(frac)
+	buf.AppendByte('s')
+}
+
+// TimeOption configures DateAwareTimeEncoder.
+type TimeOption func(*timeOptions)
+
+type timeOptions struct {
+	seconds, millis, zone bool
+}
+
+// ShowSeconds adds seconds to the time, as in 3:04:05PM.
+func ShowSeconds() TimeOption {
+	return func(o *timeOptions) { o.seconds = true }
+}
+
+// ShowMillis adds seconds and milliseconds to the time, as in
+// 3:04:05.000PM.
+func ShowMillis() TimeOption {
+	return func(o *timeOptions) { o.seconds, o.millis = true, true }
+}
+
+// ShowZone adds the timezone abbreviation to the time, as in 3:04PM UTC.
+func ShowZone() TimeOption {
+	return func(o *timeOptions) { o.zone = true }
+}
+
+// DateAwareTimeEncoder returns a time encoder writing the short time of
+// time.Kitchen, but prefixed with the date, as in 2006-01-02 3:04PM, on the
+// first entry and on every entry whose calendar day differs from the
+// previous entry's. Logs from long runs then stay unambiguous across
+// midnight without repeating the date on every line.
+//
+// As with DeltaTimeEncoder, the previous entry is the latest one encoded
+// by any encoder using the same time encoder.
+func DateAwareTimeEncoder(opts ...TimeOption) zapcore.TimeEncoder {
+	var o timeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	layout := "3:04"
+	if o.seconds {
+		layout += ":05"
+	}
+	if o.millis {
+		layout += ".000"
+	}
+	layout += "PM"
+	if o.zone {
+		layout += " MST"
+	}
+
+	var lastDay atomic.Int64
+	lastDay.Store(-1)
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		y, m, d := t.Date()
+		day := int64(y)<<9 | int64(m)<<5 | int64(d)
+		newDay := lastDay.Swap(day) != day
+		appendTime(enc, false, func(buf *buffer.Buffer) {
+			if newDay {
+				buf.AppendTime(t, time.DateOnly+" ")
+			}
+			buf.AppendTime(t, layout)
+		})
+	}
+}
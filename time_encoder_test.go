@@ -0,0 +1,172 @@
+package prettyconsole
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// encodeTimes encodes a message-only entry at each time with an encoder
+// using enc, and returns the tagged timestamp of each.
+func encodeTimes(t *testing.T, enc zapcore.TimeEncoder, times ...time.Time) []string {
+	t.Helper()
+	cfg := NewEncoderConfig()
+	cfg.EncodeTime = enc
+	e := NewEncoder(cfg, WithColourDepth(Colours16))
+	out := make([]string, len(times))
+	for i, tm := range times {
+		buf, err := e.EncodeEntry(zapcore.Entry{Time: tm, Message: "m"}, nil)
+		require.NoError(t, err)
+		out[i] = strings.SplitAfter(tagANSI(buf.String()), "<r>")[0]
+		buf.Free()
+	}
+	return out
+}
+
+func TestElapsedTimeEncoder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{
+		"<gray>+0.000s<r>",
+		"<gray>+12.345s<r>",
+		"<gray>+3600.050s<r>",
+		"<gray>-1.000s<r>",
+	}, encodeTimes(t, ElapsedTimeEncoder(start),
+		start,
+		start.Add(12*time.Second+345678*time.Microsecond),
+		start.Add(time.Hour+50*time.Millisecond),
+		start.Add(-time.Second),
+	))
+}
+
+func TestDeltaTimeEncoder(t *testing.T) {
+	base := time.Now().Add(time.Hour)
+	out := encodeTimes(t, DeltaTimeEncoder(time.Second),
+		base,
+		base.Add(150*time.Millisecond),
+		base.Add(150*time.Millisecond+12*time.Microsecond),
+		base.Add(2345*time.Millisecond),
+		base, // older than the latest entry
+	)
+	// The first delta is from when the encoder was made.
+	assert.Regexp(t, `^<yellow>Δ3\d{3}\.\d{3}s<r>$`, out[0])
+	assert.Equal(t, []string{
+		"<gray>Δ150ms<r>",
+		"<gray>Δ12µs<r>",
+		"<yellow>Δ2.194s<r>",
+		"<gray>Δ0µs<r>",
+	}, out[1:])
+
+	// Without a threshold nothing is highlighted.
+	out = encodeTimes(t, DeltaTimeEncoder(0), base, base.Add(time.Second))
+	assert.Regexp(t, `^<gray>Δ3\d{3}\.\d{3}s<r>$`, out[0])
+	assert.Equal(t, "<gray>Δ1.000s<r>", out[1])
+}
+
+func TestDeltaTimeEncoderConcurrent(t *testing.T) {
+	cfg := NewEncoderConfig()
+	cfg.EncodeTime = DeltaTimeEncoder(0)
+	enc := NewEncoder(cfg, WithColour(false))
+	base := time.Now()
+
+	// However entries interleave, the deltas add up to the time between
+	// the first entry and the latest.
+	var (
+		mu    sync.Mutex
+		total time.Duration
+		wg    sync.WaitGroup
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= 100; i++ {
+				buf, err := enc.EncodeEntry(zapcore.Entry{Time: base.Add(time.Duration(i) * time.Millisecond)}, nil)
+				assert.NoError(t, err)
+				d, err := time.ParseDuration(strings.TrimPrefix(strings.Fields(buf.String())[0], "Δ"))
+				assert.NoError(t, err)
+				buf.Free()
+				mu.Lock()
+				total += d
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	// The encoder was made a moment before base, so allow for that.
+	assert.InDelta(t, 100*time.Millisecond, total, float64(time.Millisecond))
+}
+
+func TestTimeEncodersOtherEncoders(t *testing.T) {
+	// Outside this package's encoder the time is appended as a string.
+	enc := zapcore.NewConsoleEncoder(zapcore.EncoderConfig{
+		TimeKey:    "T",
+		MessageKey: "M",
+		EncodeTime: ElapsedTimeEncoder(time.Unix(0, 0)),
+		LineEnding: "\n",
+	})
+	buf, err := enc.EncodeEntry(zapcore.Entry{Time: time.Unix(1, 5e6), Message: "m"}, []zapcore.Field{zap.Int("n", 1)})
+	require.NoError(t, err)
+	defer buf.Free()
+	assert.Equal(t, "+1.005s\tm\t{\"n\": 1}\n", stripANSI(buf.String()))
+}
+
+func TestAppendDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                       "0µs",
+		999 * time.Nanosecond:   "0µs",
+		12 * time.Microsecond:   "12µs",
+		time.Millisecond:        "1ms",
+		999 * time.Millisecond:  "999ms",
+		time.Second:             "1.000s",
+		61*time.Second + 7e6:    "61.007s",
+		time.Second + 999999999: "1.999s",
+	} {
+		buf := buffer.NewPool().Get()
+		appendDuration(buf, d)
+		assert.Equal(t, want, buf.String(), d.String())
+		buf.Free()
+	}
+}
+
+func TestDateAwareTimeEncoder(t *testing.T) {
+	day := time.Date(2024, 3, 4, 23, 59, 58, 120e6, time.UTC)
+	times := []time.Time{
+		day,
+		day.Add(time.Second),
+		day.Add(2 * time.Second), // midnight
+		day.Add(time.Hour),
+		day.Add(48 * time.Hour),
+	}
+	assert.Equal(t, []string{
+		"<gray>2024-03-04 11:59PM<r>",
+		"<gray>11:59PM<r>",
+		"<gray>2024-03-05 12:00AM<r>",
+		"<gray>12:59AM<r>",
+		"<gray>2024-03-06 11:59PM<r>",
+	}, encodeTimes(t, DateAwareTimeEncoder(), times...))
+	assert.Equal(t, []string{
+		"<gray>2024-03-04 11:59:58PM UTC<r>",
+		"<gray>11:59:59PM UTC<r>",
+		"<gray>2024-03-05 12:00:00AM UTC<r>",
+		"<gray>12:59:58AM UTC<r>",
+		"<gray>2024-03-06 11:59:58PM UTC<r>",
+	}, encodeTimes(t, DateAwareTimeEncoder(ShowSeconds(), ShowZone()), times...))
+	assert.Equal(t, []string{
+		"<gray>2024-03-04 11:59:58.120PM<r>",
+		"<gray>11:59:59.120PM<r>",
+	}, encodeTimes(t, DateAwareTimeEncoder(ShowMillis()), times[:2]...))
+
+	// The day is the calendar day where the entry was logged.
+	est := time.FixedZone("EST", -5*60*60)
+	assert.Equal(t, []string{
+		"<gray>2024-03-04 6:59PM<r>",
+		"<gray>7:00PM<r>",
+	}, encodeTimes(t, DateAwareTimeEncoder(), times[0].In(est), times[2].In(est)))
+}
@@ -0,0 +1,176 @@
+package prettyconsole
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TraceLevel logs messages more verbose than zap's DebugLevel, such as
+// every step of a loop. zap has no such level, but any zapcore.Level can
+// be logged at; this encoder writes this one as TRC.
+const TraceLevel = zapcore.DebugLevel - 1
+
+// SlogTraceLevel is the slog level Handler writes at TraceLevel. Any
+// level below slog.LevelDebug is mapped to TraceLevel.
+const SlogTraceLevel = slog.LevelDebug - 4
+
+// Trace logs a message at TraceLevel. It is logger.Log(TraceLevel, ...)
+// for versions of zap without it, reporting its own caller.
+func Trace(logger *zap.Logger, msg string, fields ...zap.Field) {
+	// Skipping this frame costs a logger clone, so only when it is used.
+	if !logger.Core().Enabled(TraceLevel) {
+		return
+	}
+	if ce := logger.WithOptions(zap.AddCallerSkip(1)).Check(TraceLevel, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// Tracew logs a message and key-value pairs at TraceLevel, like the
+// SugaredLogger's Debugw.
+func Tracew(s *zap.SugaredLogger, msg string, keysAndValues ...interface{}) {
+	if s.Desugar().Core().Enabled(TraceLevel) {
+		s.WithOptions(zap.AddCallerSkip(1)).Logw(TraceLevel, msg, keysAndValues...)
+	}
+}
+
+// Tracef logs a formatted message at TraceLevel, like the SugaredLogger's
+// Debugf.
+func Tracef(s *zap.SugaredLogger, template string, args ...interface{}) {
+	if s.Desugar().Core().Enabled(TraceLevel) {
+		s.WithOptions(zap.AddCallerSkip(1)).Logf(TraceLevel, template, args...)
+	}
+}
+
+// ParseLevel parses a level, such as "info" or "trace", by the labels of
+// zap's levels and those given to RegisterLevel, in any case. zap's own
+// names come first, so they always parse as zap does; the labels of
+// registered levels are tried after, from the lowest level up.
+func ParseLevel(text string) (zapcore.Level, error) {
+	l, err := zapcore.ParseLevel(text)
+	if err == nil || text == "" {
+		return l, err
+	}
+	reg := registeredLevels()
+	for _, l := range slices.Sorted(maps.Keys(reg)) {
+		labels := reg[l].labels
+		if strings.EqualFold(text, labels.Full) || strings.EqualFold(text, labels.Short) {
+			return l, nil
+		}
+	}
+	return l, err
+}
+
+// levelText returns the name ParseLevel reads back: the level's full label
+// in lower case, or zap's name for levels without one.
+func levelText(l zapcore.Level) string {
+	if info, ok := registeredLevels()[l]; ok {
+		if text := info.labels.label(LevelLower); text != "" {
+			return text
+		}
+	}
+	return l.String()
+}
+
+// AtomicLevel is a zap.AtomicLevel whose text form is read with ParseLevel
+// and written as ParseLevel reads it, so "trace" and registered levels
+// survive configuration files.
+type AtomicLevel struct {
+	zap.AtomicLevel
+}
+
+// NewAtomicLevelAt returns an AtomicLevel set to l.
+func NewAtomicLevelAt(l zapcore.Level) AtomicLevel {
+	return AtomicLevel{zap.NewAtomicLevelAt(l)}
+}
+
+// UnmarshalText sets the level from its name. Like zap.AtomicLevel, a zero
+// AtomicLevel is allocated, so it can be unmarshalled into directly.
+func (a *AtomicLevel) UnmarshalText(text []byte) error {
+	l, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	if a.AtomicLevel == (zap.AtomicLevel{}) {
+		a.AtomicLevel = zap.NewAtomicLevelAt(l)
+	} else {
+		a.SetLevel(l)
+	}
+	return nil
+}
+
+// MarshalText writes the level's name.
+func (a AtomicLevel) MarshalText() ([]byte, error) {
+	return []byte(levelText(a.Level())), nil
+}
+
+// String returns the level's name.
+func (a AtomicLevel) String() string {
+	return levelText(a.Level())
+}
+
+// Config is a zap.Config whose level is read with ParseLevel, so
+// configuration files can ask for "trace" or a registered level. It has
+// zap.Config's fields, rather than embedding it, so that JSON and YAML
+// decoders see a single level key; see zap.Config for what each does.
+type Config struct {
+	Level             AtomicLevel            `json:"level" yaml:"level"`
+	Development       bool                   `json:"development" yaml:"development"`
+	DisableCaller     bool                   `json:"disableCaller" yaml:"disableCaller"`
+	DisableStacktrace bool                   `json:"disableStacktrace" yaml:"disableStacktrace"`
+	Sampling          *zap.SamplingConfig    `json:"sampling" yaml:"sampling"`
+	Encoding          string                 `json:"encoding" yaml:"encoding"`
+	EncoderConfig     zapcore.EncoderConfig  `json:"encoderConfig" yaml:"encoderConfig"`
+	OutputPaths       []string               `json:"outputPaths" yaml:"outputPaths"`
+	ErrorOutputPaths  []string               `json:"errorOutputPaths" yaml:"errorOutputPaths"`
+	InitialFields     map[string]interface{} `json:"initialFields" yaml:"initialFields"`
+}
+
+// ConfigFrom returns a Config with the settings of c, such as those of
+// NewConfig, for a configuration file to be decoded on top of. The level
+// is shared with c.
+func ConfigFrom(c zap.Config) Config {
+	return Config{
+		Level:             AtomicLevel{c.Level},
+		Development:       c.Development,
+		DisableCaller:     c.DisableCaller,
+		DisableStacktrace: c.DisableStacktrace,
+		Sampling:          c.Sampling,
+		Encoding:          c.Encoding,
+		EncoderConfig:     c.EncoderConfig,
+		OutputPaths:       c.OutputPaths,
+		ErrorOutputPaths:  c.ErrorOutputPaths,
+		InitialFields:     c.InitialFields,
+	}
+}
+
+// ZapConfig returns the configuration as a zap.Config. A Config without a
+// level logs at InfoLevel.
+func (c Config) ZapConfig() zap.Config {
+	level := c.Level.AtomicLevel
+	if level == (zap.AtomicLevel{}) {
+		level = zap.NewAtomicLevel()
+	}
+	return zap.Config{
+		Level:             level,
+		Development:       c.Development,
+		DisableCaller:     c.DisableCaller,
+		DisableStacktrace: c.DisableStacktrace,
+		Sampling:          c.Sampling,
+		Encoding:          c.Encoding,
+		EncoderConfig:     c.EncoderConfig,
+		OutputPaths:       c.OutputPaths,
+		ErrorOutputPaths:  c.ErrorOutputPaths,
+		InitialFields:     c.InitialFields,
+	}
+}
+
+// Build builds a logger from the configuration, as zap.Config's Build.
+func (c Config) Build(opts ...zap.Option) (*zap.Logger, error) {
+	return c.ZapConfig().Build(opts...)
+}
@@ -0,0 +1,162 @@
+package prettyconsole
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// traceLogger logs at lvl to a plain pretty encoder, with callers.
+func traceLogger(lvl zapcore.Level) (*zap.Logger, *bytes.Buffer) {
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	cfg.CallerKey = "C"
+	var buf bytes.Buffer
+	core := zapcore.NewCore(NewEncoder(cfg, WithColour(false)), zapcore.AddSync(&buf), lvl)
+	return zap.New(core, zap.AddCaller()), &buf
+}
+
+func TestTrace(t *testing.T) {
+	logger, buf := traceLogger(TraceLevel)
+	Trace(logger, "step", zap.Int("i", 1))
+	Tracew(logger.Sugar(), "step", "i", 2)
+	Tracef(logger.Sugar(), "step %d", 3)
+	assert.Equal(t, "TRC trace_test.go:29 > step i=1\n"+
+		"TRC trace_test.go:30 > step i=2\n"+
+		"TRC trace_test.go:31 > step 3\n", buf.String())
+
+	logger, buf = traceLogger(zapcore.DebugLevel)
+	Trace(logger, "step")
+	Tracew(logger.Sugar(), "step")
+	Tracef(logger.Sugar(), "step")
+	assert.Empty(t, buf.String())
+}
+
+func TestParseLevel(t *testing.T) {
+	for text, want := range map[string]zapcore.Level{
+		"trace":  TraceLevel,
+		"TRACE":  TraceLevel,
+		"trc":    TraceLevel,
+		"info":   zapcore.InfoLevel,
+		"Wrn":    zapcore.WarnLevel,
+		"dpanic": zapcore.DPanicLevel,
+		"":       zapcore.InfoLevel, // as in zap
+	} {
+		l, err := ParseLevel(text)
+		require.NoError(t, err, text)
+		assert.Equal(t, want, l, text)
+	}
+	_, err := ParseLevel("loud")
+	assert.Error(t, err)
+
+	registerTestLevel(t, LevelLabels{Full: "AUDIT"}, Style{})
+	l, err := ParseLevel("audit")
+	require.NoError(t, err)
+	assert.Equal(t, auditLevel, l)
+
+	// zap's names win over registered labels, and a label shared by
+	// several levels is the lowest of them, whatever the map order.
+	RegisterLevel(auditLevel+2, LevelLabels{Short: "INFO", Full: "AUDIT"}, Style{})
+	RegisterLevel(auditLevel+1, LevelLabels{Full: "AUDIT"}, Style{})
+	for range 10 {
+		l, err = ParseLevel("audit")
+		require.NoError(t, err)
+		assert.Equal(t, auditLevel, l)
+		l, err = ParseLevel("info")
+		require.NoError(t, err)
+		assert.Equal(t, zapcore.InfoLevel, l)
+	}
+
+	// JSON log lines are decoded with it too.
+	ent, _, err := DecodeJSONEntry([]byte(`{"level":"trace","msg":"m"}`))
+	require.NoError(t, err)
+	assert.Equal(t, TraceLevel, ent.Level)
+}
+
+func TestAtomicLevelText(t *testing.T) {
+	var a AtomicLevel
+	require.NoError(t, a.UnmarshalText([]byte("trace")))
+	assert.Equal(t, TraceLevel, a.Level())
+	assert.Equal(t, "trace", a.String())
+
+	// Unmarshalling again changes the level in place.
+	shared := a
+	require.NoError(t, a.UnmarshalText([]byte("warn")))
+	assert.Equal(t, zapcore.WarnLevel, shared.Level())
+	assert.Error(t, a.UnmarshalText([]byte("loud")))
+
+	text, err := NewAtomicLevelAt(TraceLevel).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "trace", string(text))
+}
+
+func TestConfigTraceLevel(t *testing.T) {
+	var cfg Config
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"level": "trace",
+		"encoding": "pretty_console_monochrome",
+		"encoderConfig": {"messageKey": "M", "levelKey": "L"},
+		"outputPaths": ["stderr"]
+	}`), &cfg))
+	assert.Equal(t, TraceLevel, cfg.Level.Level())
+	assert.Equal(t, "pretty_console_monochrome", cfg.Encoding)
+
+	logger, err := cfg.Build()
+	require.NoError(t, err)
+	assert.True(t, logger.Core().Enabled(TraceLevel))
+}
+
+func TestConfigYAML(t *testing.T) {
+	// Decoded on top of defaults, as a service would load its config.
+	cfg := ConfigFrom(NewConfig())
+	require.NoError(t, yaml.Unmarshal([]byte(`
+level: trace
+encoding: pretty_console_monochrome
+encoderConfig:
+  messageKey: M
+  levelKey: L
+`), &cfg))
+	assert.Equal(t, TraceLevel, cfg.Level.Level())
+	assert.Equal(t, "pretty_console_monochrome", cfg.Encoding)
+	assert.Equal(t, "M", cfg.EncoderConfig.MessageKey)
+	assert.Equal(t, []string{"stderr"}, cfg.OutputPaths, "unset keys keep their defaults")
+
+	logger, err := cfg.Build()
+	require.NoError(t, err)
+	assert.True(t, logger.Core().Enabled(TraceLevel))
+
+	// The level is written as it is read.
+	out, err := yaml.Marshal(struct {
+		Level AtomicLevel `yaml:"level"`
+	}{cfg.Level})
+	require.NoError(t, err)
+	assert.Equal(t, "level: trace\n", string(out))
+	var back Config
+	require.NoError(t, yaml.Unmarshal(out, &back))
+	assert.Equal(t, TraceLevel, back.Level.Level())
+	assert.Equal(t, TraceLevel, back.ZapConfig().Level.Level())
+
+	// Without a level, loggers log at InfoLevel, as zap's default.
+	assert.Equal(t, zapcore.InfoLevel, Config{}.ZapConfig().Level.Level())
+}
+
+func TestSlogTraceLevel(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	h := NewHandler(&buf, &HandlerOptions{
+		Level:         SlogTraceLevel,
+		EncoderConfig: &cfg,
+		Options:       []Option{WithColour(false)},
+	})
+	slog.New(h).Log(context.Background(), SlogTraceLevel, "step")
+	assert.Equal(t, "TRC > step\n", buf.String())
+}
@@ -0,0 +1,77 @@
+package prettyconsole
+
+import (
+	"strconv"
+	"time"
+)
+
+// valueFormat is how time.Time and time.Duration field values are written.
+// It is separate from the EncoderConfig's EncodeTime, which renders the
+// entry's own timestamp, so fields can be precise without cluttering the
+// preamble.
+type valueFormat struct {
+	timeLayout string
+	// location, if set, converts times before they are written.
+	location *time.Location
+	// relative writes times as an age, such as 3m ago, from now.
+	relative bool
+	now      func() time.Time
+	// durationPrecision, if set, rounds durations to a multiple of it.
+	durationPrecision time.Duration
+}
+
+var defaultValueFormat = valueFormat{timeLayout: time.RFC3339, now: time.Now}
+
+// appendTime appends t as the options ask.
+func (f *valueFormat) appendTime(b []byte, t time.Time) []byte {
+	if f.relative {
+		return appendAge(b, f.now().Sub(t))
+	}
+	if f.location != nil {
+		t = t.In(f.location)
+	}
+	return t.AppendFormat(b, f.timeLayout)
+}
+
+func (f *valueFormat) duration(d time.Duration) time.Duration {
+	if f.durationPrecision > 0 {
+		return d.Round(f.durationPrecision)
+	}
+	return d
+}
+
+// appendAge appends how long ago a time was, in its largest whole unit:
+// 3m ago, or in 2h for a time in the future.
+func appendAge(b []byte, d time.Duration) []byte {
+	future := d < 0
+	if future {
+		b = append(b, "in "...)
+		d = -d
+	}
+	var n int64
+	var unit string
+	switch {
+	case d >= 24*time.Hour:
+		n, unit = int64(d/(24*time.Hour)), "d"
+	case d >= time.Hour:
+		n, unit = int64(d/time.Hour), "h"
+	case d >= time.Minute:
+		n, unit = int64(d/time.Minute), "m"
+	case d >= time.Second:
+		n, unit = int64(d/time.Second), "s"
+	default:
+		n, unit = d.Milliseconds(), "ms"
+	}
+	b = strconv.AppendInt(b, n, 10)
+	b = append(b, unit...)
+	if !future {
+		b = append(b, " ago"...)
+	}
+	return b
+}
+
+// appendTimeValue writes a time field value.
+func (e *prettyConsoleEncoder) appendTimeValue(t time.Time) {
+	var scratch [64]byte
+	_, _ = e.buf.Write(e.opts.values.appendTime(scratch[:0], t))
+}
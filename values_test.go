@@ -0,0 +1,90 @@
+package prettyconsole
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestValueTimeFormat(t *testing.T) {
+	when := time.Date(2024, 3, 4, 15, 4, 5, 123456789, time.FixedZone("EST", -5*60*60))
+	fields := []zap.Field{
+		zap.Time("t", when),
+		zap.Times("ts", []time.Time{when}),
+		zap.Reflect("r", struct{ At time.Time }{when}),
+	}
+
+	out := encodeWith(t, nil, fields...)
+	assert.Contains(t, out, "t=2024-03-04T15:04:05-05:00")
+	assert.Contains(t, out, "ts=[2024-03-04T15:04:05-05:00]")
+	assert.Contains(t, out, `At: "2024-03-04T15:04:05-05:00"`)
+
+	out = encodeWith(t, []Option{
+		WithValueTimeLayout("2006-01-02T15:04:05.000000000Z07:00"),
+		WithValueTimeLocation(time.UTC),
+	}, fields...)
+	assert.Contains(t, out, "t=2024-03-04T20:04:05.123456789Z")
+	assert.Contains(t, out, "ts=[2024-03-04T20:04:05.123456789Z]")
+	assert.Contains(t, out, `At: "2024-03-04T20:04:05.123456789Z"`)
+
+	relative := func(o *options) { o.values.now = func() time.Time { return when.Add(3*time.Minute + 20*time.Second) } }
+	out = encodeWith(t, []Option{WithRelativeValueTimes(true), relative}, fields...)
+	assert.Contains(t, out, "t=3m ago")
+	assert.Contains(t, out, "ts=[3m ago]")
+	assert.Contains(t, out, `At: "3m ago"`)
+}
+
+func TestRelativeValueTimesInContext(t *testing.T) {
+	// Ages in With context are worked out for each entry, even the ones
+	// without fields of their own that reuse the context's rendering.
+	started := time.Now()
+	now := started
+	cfg := NewEncoderConfig()
+	cfg.TimeKey = zapcore.OmitKey
+	enc := NewEncoder(cfg, WithColour(false), WithRelativeValueTimes(true),
+		func(o *options) { o.values.now = func() time.Time { return now } })
+	var buf bytes.Buffer
+	logger := zap.New(zapcore.NewCore(enc, zapcore.AddSync(&buf), zapcore.DebugLevel)).
+		With(zap.Time("started", started))
+
+	logger.Info("m")
+	now = now.Add(1100 * time.Millisecond)
+	logger.Info("m")
+	logger.Info("m", zap.Int("i", 1))
+	assert.Equal(t, "INF > m started=0ms ago\n"+
+		"INF > m started=1s ago\n"+
+		"INF > m i=1 started=1s ago\n", buf.String())
+}
+
+func TestValueDurationPrecision(t *testing.T) {
+	d := 1234567 * time.Microsecond
+	out := encodeWith(t, []Option{WithValueDurationPrecision(10 * time.Millisecond)},
+		zap.Duration("d", d),
+		zap.Durations("ds", []time.Duration{d}),
+		zap.Reflect("r", struct{ D time.Duration }{d}),
+	)
+	assert.Contains(t, out, "d=1.23s")
+	assert.Contains(t, out, "ds=[1.23s]")
+	assert.Contains(t, out, `D: "1.23s"`)
+
+	// Without a precision durations are untouched.
+	assert.Contains(t, encodeWith(t, nil, zap.Duration("d", d)), "d=1.234567s")
+}
+
+func TestAppendAge(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                           "0ms ago",
+		250 * time.Millisecond:      "250ms ago",
+		59 * time.Second:            "59s ago",
+		3*time.Minute + time.Second: "3m ago",
+		5 * time.Hour:               "5h ago",
+		50 * time.Hour:              "2d ago",
+		-2 * time.Hour:              "in 2h",
+	} {
+		assert.Equal(t, want, string(appendAge(nil, d)), d.String())
+	}
+}
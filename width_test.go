@@ -0,0 +1,32 @@
+package prettyconsole
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"INFO", 4},
+		{"💡", 2},
+		{"⚡", 2},
+		{"⚠", 1},
+		{"⚠\ufe0f", 2},
+		{"⏱", 1},
+		{"⏱\ufe0f", 2},
+		{"💡\ufe0f", 2},
+		{"✔\ufe0e", 1},
+		{"👩\u200d💻", 2},
+		{"警告", 4},
+		{"ＷＲＮ", 6},
+		{"é", 1},
+		{"\ufe0f", 0},
+	} {
+		assert.Equal(t, tt.want, displayWidth(tt.s), "%+q", tt.s)
+	}
+}